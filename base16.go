@@ -1,9 +1,62 @@
 package multibase
 
-func hexEncodeToStringUpper(src []byte) string {
-	dst := make([]byte, len(src)*2)
-	hexEncodeUpper(dst, src)
-	return string(dst)
+import (
+	"bytes"
+	"encoding/hex"
+	"slices"
+)
+
+// hexCodec is the base16 encoding, decoding accepts both cases
+type hexCodec struct {
+	upper bool
+}
+
+func (c hexCodec) AppendEncode(dst, src []byte) []byte {
+	if !c.upper {
+		return hex.AppendEncode(dst, src)
+	}
+	n := hex.EncodedLen(len(src))
+	dst = slices.Grow(dst, n)
+	hexEncodeUpper(dst[len(dst):len(dst)+n], src)
+	return dst[:len(dst)+n]
+}
+
+func (hexCodec) AppendDecode(dst, src []byte) ([]byte, error) {
+	out, err := hex.AppendDecode(dst, src)
+	switch e := err.(type) {
+	case nil:
+		return out, nil
+	case hex.InvalidByteError:
+		return dst, corruptInputError{offset: bytes.IndexByte(src, byte(e))}
+	default:
+		return dst, corruptInputError{len(src), errInputLength}
+	}
+}
+
+// validate checks src like hex.Decode: the first invalid character is
+// reported before an odd length
+func (hexCodec) validate(src []byte) error {
+	for i, c := range src {
+		if !isHexDigit(c) {
+			return corruptInputError{offset: i}
+		}
+	}
+	if len(src)%2 == 1 {
+		return corruptInputError{len(src), errInputLength}
+	}
+	return nil
+}
+
+func isHexDigit(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func (hexCodec) EncodedLen(n int) int {
+	return hex.EncodedLen(n)
+}
+
+func (hexCodec) DecodedLen(src []byte) (min, max int) {
+	return hex.DecodedLen(len(src)), hex.DecodedLen(len(src))
 }
 
 var hexTableUppers = [16]byte{
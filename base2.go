@@ -1,17 +1,26 @@
 package multibase
 
 import (
-	"fmt"
-	"strconv"
-	"strings"
+	"slices"
 )
 
-// binaryEncodeToString takes an array of bytes and returns
-// multibase binary representation
-func binaryEncodeToString(src []byte) string {
-	dst := make([]byte, len(src)*8)
-	encodeBinary(dst, src)
-	return string(dst)
+// binaryCodec is the multibase binary representation, every byte is
+// written as 8 digits most significant bit first
+type binaryCodec struct{}
+
+func (binaryCodec) AppendEncode(dst, src []byte) []byte {
+	n := len(src) * 8
+	dst = slices.Grow(dst, n)
+	encodeBinary(dst[len(dst):len(dst)+n], src)
+	return dst[:len(dst)+n]
+}
+
+func (binaryCodec) EncodedLen(n int) int {
+	return n * 8
+}
+
+func (binaryCodec) DecodedLen(src []byte) (min, max int) {
+	return (len(src) + 7) / 8, (len(src) + 7) / 8
 }
 
 // encodeBinary takes the src and dst bytes and converts each
@@ -28,25 +37,39 @@ func encodeBinary(dst []byte, src []byte) {
 	}
 }
 
-// decodeBinaryString takes multibase binary representation
-// and returns a byte array
-func decodeBinaryString(s string) ([]byte, error) {
-	if len(s)&7 != 0 {
-		// prepend the padding
-		s = strings.Repeat("0", 8-len(s)&7) + s
-	}
-
-	data := make([]byte, len(s)>>3)
+// AppendDecode takes multibase binary representation and appends the
+// bytes to dst. Input that isn't a multiple of 8 digits is left padded
+// with zeros.
+func (binaryCodec) AppendDecode(dst, src []byte) ([]byte, error) {
+	dst = slices.Grow(dst, (len(src)+7)/8)
 
-	for i, dstIndex := 0, 0; i < len(s); i = i + 8 {
-		value, err := strconv.ParseInt(s[i:i+8], 2, 0)
-		if err != nil {
-			return nil, fmt.Errorf("error while conversion: %s", err)
+	// the first byte takes the digits that don't fill a whole byte
+	width := len(src) & 7
+	if width == 0 {
+		width = 8
+	}
+	for i := 0; i < len(src); i += width {
+		if i > 0 {
+			width = 8
 		}
-
-		data[dstIndex] = byte(value)
-		dstIndex++
+		var value byte
+		for j := i; j < i+width; j++ {
+			c := src[j]
+			if c != '0' && c != '1' {
+				return dst, corruptInputError{offset: j}
+			}
+			value = value<<1 | (c - '0')
+		}
+		dst = append(dst, value)
 	}
+	return dst, nil
+}
 
-	return data, nil
+func (binaryCodec) validate(src []byte) error {
+	for i, c := range src {
+		if c != '0' && c != '1' {
+			return corruptInputError{offset: i}
+		}
+	}
+	return nil
 }
@@ -1,8 +1,7 @@
 package multibase
 
 import (
-	"strconv"
-	"strings"
+	"slices"
 	"unicode/utf8"
 )
 
@@ -52,44 +51,59 @@ func init() {
 	}
 }
 
-func base256emojiEncode(in []byte) string {
+// base256emojiCodec maps every byte to one emoji of base256emojiTable
+type base256emojiCodec struct{}
+
+func (base256emojiCodec) AppendEncode(dst, src []byte) []byte {
 	var l int
-	for _, v := range in {
+	for _, v := range src {
 		l += utf8.RuneLen(base256emojiTable[v])
 	}
-	var out strings.Builder
-	out.Grow(l)
-	for _, v := range in {
-		out.WriteRune(base256emojiTable[v])
+	dst = slices.Grow(dst, l)
+	for _, v := range src {
+		dst = utf8.AppendRune(dst, base256emojiTable[v])
 	}
-	return out.String()
+	return dst
 }
 
-type base256emojiCorruptInputError struct {
-	index int
-	char  rune
+// EncodedLen returns the maximum length of the encoding of n bytes, the
+// emojis being three or four bytes long
+func (base256emojiCodec) EncodedLen(n int) int {
+	return n * utf8.UTFMax
 }
 
-func (e base256emojiCorruptInputError) Error() string {
-	return "illegal base256emoji data at input byte " + strconv.FormatInt(int64(e.index), 10) + ", char: '" + string(e.char) + "'"
+func (base256emojiCodec) DecodedLen(src []byte) (min, max int) {
+	var n int
+	for _, c := range src {
+		if utf8.RuneStart(c) {
+			n++
+		}
+	}
+	return n, n
 }
 
-func (e base256emojiCorruptInputError) String() string {
-	return e.Error()
+func (c base256emojiCodec) AppendDecode(dst, src []byte) ([]byte, error) {
+	l, _ := c.DecodedLen(src)
+	dst = slices.Grow(dst, l)
+	for stri := 0; stri < len(src); {
+		r, n := utf8.DecodeRune(src[stri:])
+		v, ok := base256emojiReverseTable[r]
+		if !ok {
+			return dst, corruptInputError{offset: stri}
+		}
+		dst = append(dst, v)
+		stri += n
+	}
+	return dst, nil
 }
 
-func base256emojiDecode(in string) ([]byte, error) {
-	out := make([]byte, utf8.RuneCountInString(in))
-	var stri int
-	for i := 0; len(in) > 0; i++ {
-		r, n := utf8.DecodeRuneInString(in)
-		in = in[n:]
-		var ok bool
-		out[i], ok = base256emojiReverseTable[r]
-		if !ok {
-			return nil, base256emojiCorruptInputError{stri, r}
+func (base256emojiCodec) validate(src []byte) error {
+	for stri := 0; stri < len(src); {
+		r, n := utf8.DecodeRune(src[stri:])
+		if _, ok := base256emojiReverseTable[r]; !ok {
+			return corruptInputError{offset: stri}
 		}
 		stri += n
 	}
-	return out, nil
+	return nil
 }
@@ -1,6 +1,9 @@
 package multibase
 
 import (
+	"bytes"
+	"slices"
+
 	b32 "github.com/multiformats/go-base32"
 )
 
@@ -15,3 +18,184 @@ var base32HexLowerNoPad = base32HexLowerPad.WithPadding(b32.NoPadding)
 
 var base32HexUpperPad = b32.NewEncodingCI("0123456789ABCDEFGHIJKLMNOPQRSTUV")
 var base32HexUpperNoPad = base32HexUpperPad.WithPadding(b32.NoPadding)
+
+// base32ZNoPad is z-base-32, a permutation of the base32 alphabet
+// designed to be easier for humans to read and type
+var base32ZNoPad = b32.NewEncodingCI("ybndrfg8ejkmcpqxot1uwisza345h769").WithPadding(b32.NoPadding)
+
+// base32Codec appends with one of the encodings above. go-base32 can
+// not decode without allocating, so decoding is done here, case
+// insensitively and with the same results as go-base32.
+type base32Codec struct {
+	enc       *b32.Encoding
+	padded    bool
+	decodeMap [256]byte
+}
+
+func newBase32Codec(enc *b32.Encoding, padded bool) *base32Codec {
+	c := &base32Codec{enc: enc, padded: padded}
+	for i := range c.decodeMap {
+		c.decodeMap[i] = 0xFF
+	}
+	alphabet := enc.Alphabet()
+	for i := 0; i < len(alphabet); i++ {
+		c.decodeMap[asciiToLower(alphabet[i])] = byte(i)
+		c.decodeMap[asciiToUpper(alphabet[i])] = byte(i)
+	}
+	return c
+}
+
+func asciiToLower(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 32
+	}
+	return c
+}
+
+func asciiToUpper(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - 32
+	}
+	return c
+}
+
+func (c *base32Codec) AppendEncode(dst, src []byte) []byte {
+	n := c.enc.EncodedLen(len(src))
+	dst = slices.Grow(dst, n)
+	c.enc.Encode(dst[len(dst):len(dst)+n], src)
+	return dst[:len(dst)+n]
+}
+
+func (c *base32Codec) EncodedLen(n int) int {
+	return c.enc.EncodedLen(n)
+}
+
+// DecodedLen returns the length of the data decoded from src, which
+// only depends on the symbols before the padding: every complete block
+// of 8 symbols gives 5 bytes, and the last block 1 byte per 8 full bits
+// except for 1, 3 and 6 symbols, which are dropped like go-base32 does
+// for the unpadded encodings.
+func (c *base32Codec) DecodedLen(src []byte) (min, max int) {
+	if c.padded {
+		if i := bytes.IndexByte(src, '='); i >= 0 {
+			src = src[:i]
+		}
+	}
+	n := len(src) - bytes.Count(src, []byte{'\r'}) - bytes.Count(src, []byte{'\n'})
+	n = n/8*5 + base32TailLen[n%8]
+	return n, n
+}
+
+// base32TailLen is the number of bytes decoded from an incomplete block
+var base32TailLen = [8]int{0, 0, 1, 0, 2, 3, 0, 4}
+
+// symbolCount returns the number of symbols in src, not counting the
+// line breaks and padding skipped by the base64 decoders
+func symbolCount(src []byte) int {
+	n := len(src) - bytes.Count(src, []byte{'\r'}) - bytes.Count(src, []byte{'\n'})
+	for i := len(src) - 1; i >= 0; i-- {
+		if src[i] == '=' {
+			n--
+		} else if src[i] != '\r' && src[i] != '\n' {
+			break
+		}
+	}
+	return n
+}
+
+// AppendDecode decodes src like go-base32 does, block by block, without
+// allocating: line breaks are skipped, the symbols of an incomplete last
+// block that don't make a byte are dropped, and the padding ends the
+// data, anything after it is ignored.
+func (c *base32Codec) AppendDecode(dst, src []byte) ([]byte, error) {
+	l, _ := c.DecodedLen(src)
+	return c.decode(slices.Grow(dst, l), src, true)
+}
+
+func (c *base32Codec) validate(src []byte) error {
+	_, err := c.decode(nil, src, false)
+	return err
+}
+
+// decode appends the data decoded from src to dst, or only checks src
+// if emit is false
+func (c *base32Codec) decode(dst, src []byte, emit bool) ([]byte, error) {
+	i := 0
+	for end := false; !end; {
+		var block [8]byte
+		j := 0
+		for j < 8 {
+			if i = skipNewlines(src, i); i == len(src) {
+				if j > 0 && c.padded {
+					return dst, corruptInputError{len(src), errPadding}
+				}
+				end = true
+				break
+			}
+			ch := src[i]
+			if ch == '=' && c.padded && j >= 2 {
+				if rest := len(src) - i - 1 - newlineCount(src[i+1:]); rest < 8 {
+					if err := c.checkPadding(src, i, j); err != nil {
+						return dst, err
+					}
+					end = true
+					break
+				}
+			}
+			v := c.decodeMap[ch]
+			if v == 0xFF {
+				return dst, corruptInputError{offset: i}
+			}
+			block[j] = v
+			i++
+			j++
+		}
+
+		if !emit {
+			continue
+		}
+		switch j {
+		case 8:
+			dst = append(dst, block[0]<<3|block[1]>>2, block[1]<<6|block[2]<<1|block[3]>>4,
+				block[3]<<4|block[4]>>1, block[4]<<7|block[5]<<2|block[6]>>3, block[6]<<5|block[7])
+		case 7:
+			dst = append(dst, block[0]<<3|block[1]>>2, block[1]<<6|block[2]<<1|block[3]>>4,
+				block[3]<<4|block[4]>>1, block[4]<<7|block[5]<<2|block[6]>>3)
+		case 5:
+			dst = append(dst, block[0]<<3|block[1]>>2, block[1]<<6|block[2]<<1|block[3]>>4,
+				block[3]<<4|block[4]>>1)
+		case 4:
+			dst = append(dst, block[0]<<3|block[1]>>2, block[1]<<6|block[2]<<1|block[3]>>4)
+		case 2:
+			dst = append(dst, block[0]<<3|block[1]>>2)
+		}
+	}
+	return dst, nil
+}
+
+// checkPadding checks the padding starting at src[i] of a block of j
+// symbols: it must complete the block, and 1, 3 or 6 symbols can't be
+// padded as they don't make a byte
+func (c *base32Codec) checkPadding(src []byte, i, j int) error {
+	if j == 3 || j == 6 {
+		return corruptInputError{i, errInputLength}
+	}
+	for k := i + 1; j < 7; k++ {
+		if k == len(src) {
+			return corruptInputError{len(src), errPadding}
+		}
+		switch src[k] {
+		case '\r', '\n':
+		case '=':
+			j++
+		default:
+			return corruptInputError{k, errPadding}
+		}
+	}
+	return nil
+}
+
+// newlineCount returns the number of line breaks in src
+func newlineCount(src []byte) int {
+	return bytes.Count(src, []byte{'\r'}) + bytes.Count(src, []byte{'\n'})
+}
@@ -0,0 +1,108 @@
+package multibase
+
+import (
+	"fmt"
+	"slices"
+)
+
+// base45Alphabet is the alphabet of RFC 9285, which matches the
+// alphanumeric mode of QR codes
+const base45Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+
+var base45DecodeMap [256]byte
+
+var errBase45Range = fmt.Errorf("value out of range")
+
+func init() {
+	for i := range base45DecodeMap {
+		base45DecodeMap[i] = 0xFF
+	}
+	for i := 0; i < len(base45Alphabet); i++ {
+		base45DecodeMap[base45Alphabet[i]] = byte(i)
+	}
+}
+
+// base45Codec is the RFC 9285 encoding
+type base45Codec struct{}
+
+// base45EncodedLen returns the length of the base45 encoding of n
+// bytes, every pair of bytes takes three characters and a trailing
+// single byte takes two
+func base45EncodedLen(n int) int {
+	return n/2*3 + n%2*2
+}
+
+func (base45Codec) EncodedLen(n int) int {
+	return base45EncodedLen(n)
+}
+
+func (base45Codec) DecodedLen(src []byte) (min, max int) {
+	n := len(src)/3*2 + len(src)%3/2
+	return n, n
+}
+
+func (base45Codec) AppendEncode(dst, src []byte) []byte {
+	dst = slices.Grow(dst, base45EncodedLen(len(src)))
+	for len(src) >= 2 {
+		n := uint(src[0])<<8 | uint(src[1])
+		dst = append(dst, base45Alphabet[n%45], base45Alphabet[n/45%45], base45Alphabet[n/(45*45)])
+		src = src[2:]
+	}
+	if len(src) == 1 {
+		n := uint(src[0])
+		dst = append(dst, base45Alphabet[n%45], base45Alphabet[n/45])
+	}
+	return dst
+}
+
+// AppendDecode decodes src and appends the result to dst. Triplets
+// above 65535 and pairs above 255 are rejected as required by RFC 9285.
+func (c base45Codec) AppendDecode(dst, src []byte) ([]byte, error) {
+	if len(src)%3 == 1 {
+		return dst, corruptInputError{len(src), errInputLength}
+	}
+	return c.decode(slices.Grow(dst, len(src)/3*2+len(src)%3/2), src, true)
+}
+
+func (c base45Codec) validate(src []byte) error {
+	if len(src)%3 == 1 {
+		return corruptInputError{len(src), errInputLength}
+	}
+	_, err := c.decode(nil, src, false)
+	return err
+}
+
+// decode appends the data decoded from src, of a valid length, to dst,
+// or only checks src if emit is false
+func (base45Codec) decode(dst, src []byte, emit bool) ([]byte, error) {
+	for i := 0; i < len(src); i += 3 {
+		width := min(3, len(src)-i)
+
+		var v, mul uint = 0, 1
+		for j := 0; j < width; j++ {
+			d := base45DecodeMap[src[i+j]]
+			if d == 0xFF {
+				return dst, corruptInputError{offset: i + j}
+			}
+			v += uint(d) * mul
+			mul *= 45
+		}
+
+		if width == 3 {
+			if v > 0xFFFF {
+				return dst, corruptInputError{i, errBase45Range}
+			}
+			if emit {
+				dst = append(dst, byte(v>>8), byte(v))
+			}
+		} else {
+			if v > 0xFF {
+				return dst, corruptInputError{i, errBase45Range}
+			}
+			if emit {
+				dst = append(dst, byte(v))
+			}
+		}
+	}
+	return dst, nil
+}
@@ -0,0 +1,35 @@
+package multibase
+
+import "testing"
+
+func TestBase45Vectors(t *testing.T) {
+	// Examples from RFC 9285 section 4.3
+	vectors := []struct {
+		data    string
+		encoded string
+	}{
+		{"AB", "RBB8"},
+		{"Hello!!", "R%69 VD92EX0"},
+		{"base-45", "RUJCLQE7W581"},
+		{"ietf!", "RQED8WEX0"},
+	}
+	for _, v := range vectors {
+		testEncode(t, Base45, []byte(v.data), v.encoded)
+		testDecode(t, Base45, []byte(v.data), v.encoded)
+	}
+}
+
+func TestBase45Invalid(t *testing.T) {
+	for _, s := range []string{
+		"RGGW",  // 65536 does not fit in two bytes
+		"R:::",  // largest triplet
+		"R::",   // 2024 does not fit in one byte
+		"RBB8A", // dangling single character
+		"Rbb8",  // lower case is not part of the alphabet
+	} {
+		_, _, err := Decode(s)
+		if err == nil {
+			t.Errorf("decoding %q should fail", s)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package multibase
+
+import (
+	"encoding/base64"
+)
+
+const (
+	base64Alphabet    = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+	base64URLAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+)
+
+// base64Codec adapts the standard library encodings to Codec, with the
+// decoding errors of the other codecs
+type base64Codec struct {
+	*base64.Encoding
+	decodeMap [256]byte
+	padded    bool
+}
+
+func newBase64Codec(alphabet string, padded bool) *base64Codec {
+	enc := base64.NewEncoding(alphabet)
+	if !padded {
+		enc = enc.WithPadding(base64.NoPadding)
+	}
+	c := &base64Codec{Encoding: enc, padded: padded}
+	for i := range c.decodeMap {
+		c.decodeMap[i] = 0xFF
+	}
+	for i := 0; i < len(alphabet); i++ {
+		c.decodeMap[alphabet[i]] = byte(i)
+	}
+	return c
+}
+
+func (c *base64Codec) AppendDecode(dst, src []byte) ([]byte, error) {
+	out, err := c.Encoding.AppendDecode(dst, src)
+	if e, ok := err.(base64.CorruptInputError); ok {
+		return dst, corruptInputError{offset: int(e)}
+	}
+	return out, err
+}
+
+func (*base64Codec) DecodedLen(src []byte) (min, max int) {
+	n := symbolCount(src) * 6 / 8
+	return n, n
+}
+
+// validate checks src the way the standard library decodes it, a
+// quantum of 4 symbols at a time
+func (c *base64Codec) validate(src []byte) error {
+	for si := 0; si < len(src); {
+		var err error
+		if si, err = c.validateQuantum(src, si); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateQuantum is decodeQuantum of encoding/base64 without the
+// decoding
+func (c *base64Codec) validateQuantum(src []byte, si int) (int, error) {
+	for j := 0; j < 4; j++ {
+		if len(src) == si {
+			if j == 1 || (j > 0 && c.padded) {
+				return si, corruptInputError{offset: si - j}
+			}
+			return si, nil
+		}
+		in := src[si]
+		si++
+
+		if c.decodeMap[in] != 0xFF {
+			continue
+		}
+		if in == '\n' || in == '\r' {
+			j--
+			continue
+		}
+		if in != '=' || !c.padded {
+			return si, corruptInputError{offset: si - 1}
+		}
+
+		// We've reached the end and there's padding
+		switch j {
+		case 0, 1:
+			return si, corruptInputError{offset: si - 1}
+		case 2:
+			// "==" is expected, the first "=" is already consumed
+			si = skipNewlines(src, si)
+			if si == len(src) {
+				return si, corruptInputError{offset: len(src)}
+			}
+			if src[si] != '=' {
+				return si, corruptInputError{offset: si - 1}
+			}
+			si++
+		}
+		if si = skipNewlines(src, si); si < len(src) {
+			// trailing garbage
+			return si, corruptInputError{offset: si}
+		}
+		return si, nil
+	}
+	return si, nil
+}
+
+// skipNewlines returns the index of the first byte of src from i which
+// isn't a line break
+func skipNewlines(src []byte, i int) int {
+	for i < len(src) && (src[i] == '\n' || src[i] == '\r') {
+		i++
+	}
+	return i
+}
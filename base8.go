@@ -0,0 +1,79 @@
+package multibase
+
+import (
+	"slices"
+)
+
+// octalCodec is the multibase octal representation, the input bits are
+// grouped by 3 most significant first, the same way RFC 4648 packs bits
+// for base32 and base64
+type octalCodec struct{}
+
+// octalEncodedLen returns the number of octal digits needed to
+// represent n bytes, the last digit being zero padded
+func octalEncodedLen(n int) int {
+	return (n*8 + 2) / 3
+}
+
+func (octalCodec) EncodedLen(n int) int {
+	return octalEncodedLen(n)
+}
+
+func (octalCodec) DecodedLen(src []byte) (min, max int) {
+	return len(src) * 3 / 8, len(src) * 3 / 8
+}
+
+func (octalCodec) AppendEncode(dst, src []byte) []byte {
+	dst = slices.Grow(dst, octalEncodedLen(len(src)))
+	var acc, bits uint
+	for _, b := range src {
+		acc = acc<<8 | uint(b)
+		bits += 8
+		for bits >= 3 {
+			bits -= 3
+			dst = append(dst, '0'+byte(acc>>bits&7))
+		}
+		acc &= 1<<bits - 1
+	}
+	if bits > 0 {
+		dst = append(dst, '0'+byte(acc<<(3-bits)&7))
+	}
+	return dst
+}
+
+func (octalCodec) AppendDecode(dst, src []byte) ([]byte, error) {
+	dst = slices.Grow(dst, len(src)*3/8)
+	var acc, bits uint
+	for i, c := range src {
+		if c < '0' || c > '7' {
+			return dst, corruptInputError{offset: i}
+		}
+		acc = acc<<3 | uint(c-'0')
+		bits += 3
+		if bits >= 8 {
+			bits -= 8
+			dst = append(dst, byte(acc>>bits))
+			acc &= 1<<bits - 1
+		}
+	}
+
+	return dst, octalCheckLen(src)
+}
+
+func (octalCodec) validate(src []byte) error {
+	for i, c := range src {
+		if c < '0' || c > '7' {
+			return corruptInputError{offset: i}
+		}
+	}
+	return octalCheckLen(src)
+}
+
+// octalCheckLen checks that src doesn't leave a full digit worth of bits
+// unused, which a valid encoding never does
+func octalCheckLen(src []byte) error {
+	if len(src)*3%8 >= 3 {
+		return corruptInputError{len(src), errInputLength}
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package multibase
+
+import "testing"
+
+func TestBase8InvalidLength(t *testing.T) {
+	// 1, 2, 4, 5 and 7 trailing digits carry a full unused digit worth
+	// of bits and can not be produced by the encoder.
+	for _, s := range []string{"71", "712", "71046", "710414", "71041451"} {
+		_, _, err := Decode(s)
+		if err == nil {
+			t.Errorf("decoding %q should fail", s)
+		}
+	}
+}
+
+func TestBase8Vector(t *testing.T) {
+	testEncode(t, Base8, []byte("yes mani !"), "7362625631006654133464440102")
+	testDecode(t, Base8, []byte("yes mani !"), "7362625631006654133464440102")
+}
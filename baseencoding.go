@@ -0,0 +1,164 @@
+package multibase
+
+import (
+	"io"
+	"math"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// BaseEncoding is a multibase encoding with the methods of the encodings
+// of the standard library, like base64.Encoding, so that it can replace
+// them. Encoded data always includes the multibase prefix, and decoding
+// only accepts the prefix of the encoding, within the limits of
+// DefaultDecodeOptions.
+type BaseEncoding struct {
+	codec   Codec
+	allowed []Encoding
+}
+
+// Codec returns the BaseEncoding of e, or nil if e is not supported.
+func (e Encoding) Codec() *BaseEncoding {
+	c, ok := lookupCodec(e)
+	if !ok {
+		return nil
+	}
+	return &BaseEncoding{codec: c, allowed: []Encoding{e}}
+}
+
+// Encoding returns the encoding of b.
+func (b *BaseEncoding) Encoding() Encoding {
+	return b.allowed[0]
+}
+
+// EncodedLen returns the maximum length in bytes of the multibase
+// string of n bytes, see the EncodedLen function.
+func (b *BaseEncoding) EncodedLen(n int) int {
+	return utf8.RuneLen(rune(b.Encoding())) + b.codec.EncodedLen(n)
+}
+
+// DecodedLen returns the maximum length in bytes of the data decoded
+// from a multibase string of n bytes. It is n minus the prefix for the
+// big-number encodings, as every leading zero digit decodes to a byte,
+// and for the registered encodings which don't give their bits per
+// symbol.
+func (b *BaseEncoding) DecodedLen(n int) int {
+	n -= utf8.RuneLen(rune(b.Encoding()))
+	if n <= 0 {
+		return 0
+	}
+	if bits := b.Encoding().Info().BitsPerSymbol; bits > 0 {
+		return int(math.Ceil(float64(n) * bits / 8))
+	}
+	return n
+}
+
+// Encode writes the multibase string of src to dst and returns the
+// number of bytes written, which is at most EncodedLen(len(src)). Like
+// the Encode method of base64.Encoding, it expects dst to hold at least
+// EncodedLen(len(src)) bytes and panics if the encoding doesn't fit.
+func (b *BaseEncoding) Encode(dst, src []byte) int {
+	out := b.AppendEncode(dst[:0:len(dst)], src)
+	// the codecs may grow the buffer on their own estimate
+	if unsafe.SliceData(out) != unsafe.SliceData(dst) {
+		if len(out) > len(dst) {
+			panic("multibase: destination buffer too short")
+		}
+		copy(dst, out)
+	}
+	return len(out)
+}
+
+// AppendEncode appends the multibase string of src to dst and returns
+// the extended buffer.
+func (b *BaseEncoding) AppendEncode(dst, src []byte) []byte {
+	dst = utf8.AppendRune(dst, rune(b.Encoding()))
+	return b.codec.AppendEncode(dst, src)
+}
+
+// EncodeToString returns the multibase string of src.
+func (b *BaseEncoding) EncodeToString(src []byte) string {
+	out := b.AppendEncode(make([]byte, 0, b.EncodedLen(len(src))), src)
+	return encodedString(b.codec, out)
+}
+
+// Decode decodes the multibase string src into dst and returns the
+// number of bytes written, like DecodeInto. DecodedLen(len(src)) bytes
+// are always enough.
+func (b *BaseEncoding) Decode(dst, src []byte) (n int, err error) {
+	_, n, err = decodeInto(dst, src, b.allowed)
+	return n, err
+}
+
+// AppendDecode appends the data decoded from the multibase string src to
+// dst and returns the extended buffer. On error, dst is returned
+// unchanged.
+func (b *BaseEncoding) AppendDecode(dst, src []byte) ([]byte, error) {
+	_, out, err := DefaultDecodeOptions.appendDecode(dst, src, b.allowed)
+	return out, err
+}
+
+// DecodeString returns the data decoded from the multibase string s.
+func (b *BaseEncoding) DecodeString(s string) ([]byte, error) {
+	_, out, err := DefaultDecodeOptions.appendDecode(nil, stringBytes(s), b.allowed)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// NewEncoder returns a stream encoder writing the multibase string of
+// the data written to it to w, see NewWriter. For the encodings that
+// can't be streamed, its Write and Close methods fail with
+// ErrNotStreamable.
+func (b *BaseEncoding) NewEncoder(w io.Writer) io.WriteCloser {
+	e, err := NewWriter(b.Encoding(), w)
+	if err != nil {
+		return errWriter{err}
+	}
+	return e
+}
+
+// NewDecoder returns a stream decoder reading a multibase string from r,
+// see NewReader. The prefix is only read by the first call to Read,
+// which fails if it isn't the one of b, or with ErrNotStreamable for the
+// encodings that can't be streamed.
+func (b *BaseEncoding) NewDecoder(r io.Reader) io.Reader {
+	return &baseDecoder{r: r, allowed: b.allowed}
+}
+
+// errWriter is a stream encoder failing with err
+type errWriter struct {
+	err error
+}
+
+func (w errWriter) Write([]byte) (int, error) {
+	return 0, w.err
+}
+
+func (w errWriter) Close() error {
+	return w.err
+}
+
+// baseDecoder is the stream decoder of a BaseEncoding, it creates the
+// reader of NewReader on first use
+type baseDecoder struct {
+	r       io.Reader
+	allowed []Encoding
+	d       io.Reader
+	err     error
+}
+
+func (b *baseDecoder) Read(p []byte) (int, error) {
+	if b.d == nil && b.err == nil {
+		var enc Encoding
+		enc, b.d, b.err = NewReader(b.r)
+		if b.err == nil {
+			b.err = allowedError(enc, b.allowed)
+		}
+	}
+	if b.err != nil {
+		return 0, b.err
+	}
+	return b.d.Read(p)
+}
@@ -0,0 +1,114 @@
+package multibase
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestBaseEncoding(t *testing.T) {
+	inputs := [][]byte{{}, {0}, {0, 0, 1}, []byte("hello world"), sampleBytes, bytes.Repeat([]byte{0xff}, 100)}
+	for _, info := range All() {
+		b := info.Encoding.Codec()
+		if b == nil || b.Encoding() != info.Encoding {
+			t.Fatalf("no codec for %s", info.Name)
+		}
+		for _, data := range inputs {
+			expected, err := Encode(info.Encoding, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			buf := make([]byte, b.EncodedLen(len(data)))
+			n := b.Encode(buf, data)
+			if string(buf[:n]) != expected {
+				t.Errorf("%s: Encode(%q) = %q, expected %q", info.Name, data, buf[:n], expected)
+			}
+			if s := b.EncodeToString(data); s != expected {
+				t.Errorf("%s: EncodeToString(%q) = %q, expected %q", info.Name, data, s, expected)
+			}
+			if out := b.AppendEncode([]byte("x"), data); string(out) != "x"+expected {
+				t.Errorf("%s: AppendEncode(%q) = %q", info.Name, data, out)
+			}
+
+			if len(data) == 0 && info.BigNumber {
+				continue // the empty number doesn't decode
+			}
+			dec := make([]byte, b.DecodedLen(len(expected)))
+			n, err = b.Decode(dec, []byte(expected))
+			if err != nil || !bytes.Equal(dec[:n], data) {
+				t.Errorf("%s: Decode(%q) = %q, %v", info.Name, expected, dec[:n], err)
+			}
+			if out, err := b.DecodeString(expected); err != nil || !bytes.Equal(out, data) {
+				t.Errorf("%s: DecodeString(%q) = %q, %v", info.Name, expected, out, err)
+			}
+			if out, err := b.AppendDecode([]byte("x"), []byte(expected)); err != nil || string(out) != "x"+string(data) {
+				t.Errorf("%s: AppendDecode(%q) = %q, %v", info.Name, expected, out, err)
+			}
+		}
+	}
+
+	if Encoding('q').Codec() != nil {
+		t.Error("unsupported encodings should have no codec")
+	}
+}
+
+func TestBaseEncodingErrors(t *testing.T) {
+	b := Encoding(Base16).Codec()
+	if _, err := b.DecodeString("bnbswy3dp"); !errors.Is(err, ErrEncodingNotAllowed) {
+		t.Errorf("expected ErrEncodingNotAllowed, got %v", err)
+	}
+	if _, err := b.Decode(make([]byte, 8), []byte("bnbswy3dp")); !errors.Is(err, ErrEncodingNotAllowed) {
+		t.Errorf("expected ErrEncodingNotAllowed, got %v", err)
+	}
+	if out, err := b.AppendDecode([]byte("x"), []byte("f0g")); !errors.Is(err, ErrCorruptPayload) || string(out) != "x" {
+		t.Errorf("expected ErrCorruptPayload and dst unchanged, got %q, %v", out, err)
+	}
+	if _, err := b.Decode(make([]byte, 1), []byte("f6869")); !errors.Is(err, io.ErrShortBuffer) {
+		t.Errorf("expected io.ErrShortBuffer, got %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Encode should panic when dst is too short")
+		}
+	}()
+	b.Encode(make([]byte, 4), []byte("hi"))
+}
+
+func TestBaseEncodingStream(t *testing.T) {
+	b := Encoding(Base64).Codec()
+	var buf strings.Builder
+	w := b.NewEncoder(&buf)
+	w.Write([]byte("hello "))
+	w.Write([]byte("world"))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != b.EncodeToString([]byte("hello world")) {
+		t.Errorf("unexpected stream encoding %q", buf.String())
+	}
+
+	out, err := io.ReadAll(b.NewDecoder(strings.NewReader(buf.String())))
+	if err != nil || string(out) != "hello world" {
+		t.Errorf("unexpected stream decoding %q, %v", out, err)
+	}
+
+	if _, err := io.ReadAll(b.NewDecoder(strings.NewReader("f6869"))); !errors.Is(err, ErrEncodingNotAllowed) {
+		t.Errorf("expected ErrEncodingNotAllowed, got %v", err)
+	}
+
+	b58 := Encoding(Base58BTC).Codec()
+	w = b58.NewEncoder(&buf)
+	if _, err := w.Write([]byte("hi")); !errors.Is(err, ErrNotStreamable) {
+		t.Errorf("expected ErrNotStreamable writing, got %v", err)
+	}
+	if err := w.Close(); !errors.Is(err, ErrNotStreamable) {
+		t.Errorf("expected ErrNotStreamable closing, got %v", err)
+	}
+	if _, err := io.ReadAll(b58.NewDecoder(strings.NewReader("z36UQrhJq9fNDS7DiAHM9YXqDHMPfr4EMArvt"))); !errors.Is(err, ErrNotStreamable) {
+		t.Errorf("expected ErrNotStreamable reading, got %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package multibase
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"math/rand/v2"
+	"testing"
+
+	b58 "github.com/mr-tron/base58/base58"
+	b32 "github.com/multiformats/go-base32"
+	b36 "github.com/multiformats/go-base36"
+)
+
+// baselineDecoders are the decoders used before the codecs were
+// rewritten to decode without allocating, they must keep giving the same
+// results
+var baselineDecoders = map[Encoding]func(string) ([]byte, error){
+	Base16:            hex.DecodeString,
+	Base16Upper:       hex.DecodeString,
+	Base32:            b32.RawStdEncoding.DecodeString,
+	Base32Upper:       b32.RawStdEncoding.DecodeString,
+	Base32pad:         b32.StdEncoding.DecodeString,
+	Base32padUpper:    b32.StdEncoding.DecodeString,
+	Base32hex:         b32.RawHexEncoding.DecodeString,
+	Base32hexUpper:    b32.RawHexEncoding.DecodeString,
+	Base32hexPad:      b32.HexEncoding.DecodeString,
+	Base32hexPadUpper: b32.HexEncoding.DecodeString,
+	Base36:            b36.DecodeString,
+	Base36Upper:       b36.DecodeString,
+	Base58BTC:         func(s string) ([]byte, error) { return b58.DecodeAlphabet(s, b58.BTCAlphabet) },
+	Base58Flickr:      func(s string) ([]byte, error) { return b58.DecodeAlphabet(s, b58.FlickrAlphabet) },
+	Base64:            base64.RawStdEncoding.DecodeString,
+	Base64url:         base64.RawURLEncoding.DecodeString,
+	Base64pad:         base64.StdEncoding.DecodeString,
+	Base64urlPad:      base64.URLEncoding.DecodeString,
+}
+
+func testBaselineDecode(t *testing.T, encoding Encoding, payload string) {
+	t.Helper()
+	expected, expectedErr := baselineDecoders[encoding](payload)
+	_, out, err := Decode(string(rune(encoding)) + payload)
+	// the baseline decoders may return partial data along with an error
+	if (err != nil) != (expectedErr != nil) || (err == nil && !bytes.Equal(out, expected)) {
+		t.Errorf("%s: decoding %q gave %x, %v, baseline gave %x, %v", encoding, payload, out, err, expected, expectedErr)
+	}
+}
+
+func TestBaselineDecode(t *testing.T) {
+	for _, tc := range []struct {
+		encoding Encoding
+		payload  string
+	}{
+		{Base58BTC, ""},
+		{Base58Flickr, ""},
+		{Base36, ""},
+		{Base36Upper, ""},
+		{Base32, "A"},
+		{Base32, "mzx"},
+		{Base32, "mzxw6y"},
+		{Base32, "mzxw6ytbo"},
+		{Base32, "mz\nxw\r6"},
+		{Base32, "mzxw6="},
+		{Base32pad, "mzxw6==="},
+		{Base32pad, "mzxw6===garbage"},
+		{Base32pad, "mzxw6=="},
+		{Base32pad, "mzxw6=a="},
+		{Base32pad, "mzx====="},
+		{Base32pad, "m======="},
+		{Base32pad, "mzxw6"},
+		{Base32pad, "mz\n======"},
+	} {
+		testBaselineDecode(t, tc.encoding, tc.payload)
+	}
+}
+
+func TestBaselineDecodeRandom(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	for encoding := range baselineDecoders {
+		info, _ := Lookup(encoding)
+		symbols := info.Alphabet + "=\r\n !aAzZ01"
+		payload := make([]byte, 0, 16)
+		for range 5000 {
+			payload = payload[:0]
+			for n := rng.IntN(16); n > 0; n-- {
+				payload = append(payload, symbols[rng.IntN(len(symbols))])
+			}
+			testBaselineDecode(t, encoding, string(payload))
+		}
+	}
+}
+
+func TestBaselineEncodeRandom(t *testing.T) {
+	encoders := map[Encoding]func([]byte) string{
+		Base36:       b36.EncodeToStringLc,
+		Base36Upper:  b36.EncodeToStringUc,
+		Base58BTC:    func(b []byte) string { return b58.EncodeAlphabet(b, b58.BTCAlphabet) },
+		Base58Flickr: func(b []byte) string { return b58.EncodeAlphabet(b, b58.FlickrAlphabet) },
+	}
+	rng := rand.New(rand.NewPCG(1, 2))
+	data := make([]byte, 0, 300)
+	for encoding, encode := range encoders {
+		for range 1000 {
+			data = data[:0]
+			for n := rng.IntN(300); n > 0; n-- {
+				// plenty of zeros, leading ones in particular
+				b := byte(rng.Uint32())
+				if rng.IntN(4) == 0 {
+					b = 0
+				}
+				data = append(data, b)
+			}
+			expected := string(rune(encoding)) + encode(data)
+			if s, _ := Encode(encoding, data); s != expected {
+				t.Errorf("%s: encoding %x gave %q, baseline gave %q", encoding, data, s, expected)
+			}
+		}
+	}
+}
+
+// TestBaselineDifferences lists the inputs that are intentionally
+// decoded differently
+func TestBaselineDifferences(t *testing.T) {
+	// base2 used strconv.ParseInt on each byte, which accepted a sign
+	for _, s := range []string{"0-0000001", "0+0000001"} {
+		if _, _, err := Decode(s); err == nil {
+			t.Errorf("decoding %q should fail", s)
+		}
+	}
+}
@@ -0,0 +1,356 @@
+package multibase
+
+import (
+	"encoding/binary"
+	"math"
+	"math/big"
+	"math/bits"
+	"slices"
+)
+
+// bigBase is a big-number encoding: every leading zero byte is kept as
+// a leading zero digit and the rest of the input is encoded as a single
+// big-endian number, the way base58 works.
+//
+// Conversions go through 64 bit words and chunks of as many digits as
+// fit in a word, which keeps inputs up to 128 bytes (a lot more than a
+// CID) free of allocations. Inputs from bigBaseThreshold bytes are
+// converted by divide and conquer with math/big instead, as the word
+// loops are quadratic.
+type bigBase struct {
+	alphabet  string
+	decodeMap [256]byte
+	radix     uint64
+	// pow holds the powers of radix fitting in 64 bits, the largest
+	// one is the chunk size used for conversions
+	pow    []uint64
+	digits int
+	// digitsPerByte is log(256)/log(radix) and bytesPerDigit its
+	// inverse, rounded up (or down for bytesPerDigitLow) by a tiny
+	// margin so that length bounds never miss because of floating point
+	// errors
+	digitsPerByte, bytesPerDigit, bytesPerDigitLow float64
+}
+
+var (
+	base10Codec       = newBigBase("0123456789", false)
+	base36LowerCodec  = newBigBase("0123456789abcdefghijklmnopqrstuvwxyz", true)
+	base36UpperCodec  = newBigBase("0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ", true)
+	base58BTCCodec    = newBigBase("123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz", false)
+	base58FlickrCodec = newBigBase("123456789abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ", false)
+)
+
+const bigBaseSmallWords = 16
+
+// bigBaseThreshold is the payload length in bytes from which the
+// conversions switch to divide and conquer, below it the quadratic word
+// loops are faster. It is a variable for the tests.
+var bigBaseThreshold = 1 << 10
+
+func newBigBase(alphabet string, caseInsensitive bool) *bigBase {
+	b := &bigBase{alphabet: alphabet, radix: uint64(len(alphabet))}
+	for i := range b.decodeMap {
+		b.decodeMap[i] = 0xFF
+	}
+	for i := 0; i < len(alphabet); i++ {
+		b.decodeMap[alphabet[i]] = byte(i)
+		if caseInsensitive {
+			b.decodeMap[asciiToLower(alphabet[i])] = byte(i)
+			b.decodeMap[asciiToUpper(alphabet[i])] = byte(i)
+		}
+	}
+
+	b.pow = []uint64{1}
+	for {
+		hi, lo := bits.Mul64(b.pow[len(b.pow)-1], b.radix)
+		if hi != 0 {
+			break
+		}
+		b.pow = append(b.pow, lo)
+	}
+	b.digits = len(b.pow) - 1
+
+	perByte := math.Log(256) / math.Log(float64(b.radix))
+	b.digitsPerByte = perByte * (1 + 1e-13)
+	b.bytesPerDigit = 1 / perByte * (1 + 1e-13)
+	b.bytesPerDigitLow = 1 / perByte * (1 - 1e-13)
+	return b
+}
+
+// EncodedLen returns the maximum length of the encoding of n bytes,
+// which is reached when the first byte isn't zero
+func (b *bigBase) EncodedLen(n int) int {
+	return int(math.Ceil(float64(n) * b.digitsPerByte))
+}
+
+// DecodedLen returns the bounds of the decoded length of src: leading
+// zero digits decode to one byte each, the k remaining digits make a
+// number between radix^(k-1) and radix^k - 1
+func (b *bigBase) DecodedLen(src []byte) (min, max int) {
+	zcnt := 0
+	for zcnt < len(src) && src[zcnt] == b.alphabet[0] {
+		zcnt++
+	}
+	k := len(src) - zcnt
+	if k == 0 {
+		return zcnt, zcnt
+	}
+	return zcnt + int(float64(k-1)*b.bytesPerDigitLow) + 1,
+		zcnt + int(math.Ceil(float64(k)*b.bytesPerDigit))
+}
+
+func (b *bigBase) AppendEncode(dst, src []byte) []byte {
+	zcnt := 0
+	for zcnt < len(src) && src[zcnt] == 0 {
+		zcnt++
+	}
+	payload := src[zcnt:]
+
+	size := zcnt + b.EncodedLen(len(payload))
+	dst = slices.Grow(dst, size)
+	out := dst[len(dst) : len(dst)+size]
+	for i := 0; i < zcnt; i++ {
+		out[i] = b.alphabet[0]
+	}
+
+	var pos int
+	if len(payload) >= bigBaseThreshold {
+		b.encodeBig(out[zcnt:], new(big.Int).SetBytes(payload), b.powers(size-zcnt))
+		pos = zcnt
+		for pos < size && out[pos] == b.alphabet[0] {
+			pos++
+		}
+	} else {
+		var small [bigBaseSmallWords]uint64
+		words := small[:0]
+		if wc := (len(payload) + 7) / 8; wc <= len(small) {
+			words = small[:wc]
+		} else {
+			words = make([]uint64, wc)
+		}
+		loadWords(words, payload)
+		pos = b.putDigits(out, words)
+	}
+
+	n := copy(out[zcnt:], out[pos:])
+	return dst[:len(dst)+zcnt+n]
+}
+
+// loadWords loads payload as big-endian 64 bit words, the first word
+// takes the bytes left over
+func loadWords(words []uint64, payload []byte) {
+	if len(payload) == 0 {
+		return
+	}
+	first := len(payload) % 8
+	if first == 0 {
+		first = 8
+	}
+	words[0] = 0
+	for _, c := range payload[:first] {
+		words[0] = words[0]<<8 | uint64(c)
+	}
+	for i, off := 1, first; off < len(payload); i, off = i+1, off+8 {
+		words[i] = binary.BigEndian.Uint64(payload[off:])
+	}
+}
+
+// putDigits writes the number made of the big-endian words at the end of
+// out and returns the position of its most significant digit, the words
+// are overwritten
+func (b *bigBase) putDigits(out []byte, words []uint64) int {
+	// Divide by the chunk size until nothing is left, every remainder
+	// gives the next digits of the result, least significant first
+	chunk := b.pow[b.digits]
+	pos := len(out)
+	for start := 0; start < len(words); {
+		var rem uint64
+		for i := start; i < len(words); i++ {
+			words[i], rem = bits.Div64(rem, words[i], chunk)
+		}
+		for start < len(words) && words[start] == 0 {
+			start++
+		}
+		// the most significant chunk has no leading zero digits
+		for j := 0; j < b.digits && (rem != 0 || start < len(words)); j++ {
+			pos--
+			out[pos] = b.alphabet[rem%b.radix]
+			rem /= b.radix
+		}
+	}
+	return pos
+}
+
+func (b *bigBase) AppendDecode(dst, src []byte) ([]byte, error) {
+	// an empty string is not a number, go-base36 and mr-tron/base58
+	// reject it too
+	if len(src) == 0 {
+		return dst, corruptInputError{0, errInputLength}
+	}
+	zcnt := 0
+	for zcnt < len(src) && src[zcnt] == b.alphabet[0] {
+		zcnt++
+	}
+	payload := src[zcnt:]
+
+	if len(payload) >= b.EncodedLen(bigBaseThreshold) {
+		x, err := b.decodeBig(payload, zcnt, b.powers(len(payload)))
+		if err != nil {
+			return dst, err
+		}
+		n := zcnt + (x.BitLen()+7)/8
+		dst = slices.Grow(dst, n)
+		for i := 0; i < zcnt; i++ {
+			dst = append(dst, 0)
+		}
+		x.FillBytes(dst[len(dst) : len(dst)+n-zcnt])
+		return dst[:len(dst)+n-zcnt], nil
+	}
+
+	var small [bigBaseSmallWords]uint64
+	words := small[:0]
+	if _, n := b.DecodedLen(payload); n/8+1 > len(small) {
+		words = make([]uint64, 0, n/8+1)
+	}
+	words, err := b.addDigits(words, payload, zcnt)
+	if err != nil {
+		return dst, err
+	}
+
+	// Write the leading zeros followed by the number, big-endian
+	top := len(words) - 1
+	n := zcnt
+	if top >= 0 {
+		n += (bits.Len64(words[top])+7)/8 + 8*top
+	}
+	dst = slices.Grow(dst, n)
+	for i := 0; i < zcnt; i++ {
+		dst = append(dst, 0)
+	}
+	if top >= 0 {
+		for s := (bits.Len64(words[top]) + 7) / 8; s > 0; s-- {
+			dst = append(dst, byte(words[top]>>(8*(s-1))))
+		}
+		for k := top - 1; k >= 0; k-- {
+			dst = binary.BigEndian.AppendUint64(dst, words[k])
+		}
+	}
+	return dst, nil
+}
+
+// addDigits accumulates the digits of payload, a chunk at a time, in the
+// little-endian 64 bit words, offset is the position of payload in the
+// input for errors
+func (b *bigBase) addDigits(words []uint64, payload []byte, offset int) ([]uint64, error) {
+	width := len(payload) % b.digits
+	if width == 0 {
+		width = b.digits
+	}
+	for i := 0; i < len(payload); i, width = i+width, b.digits {
+		var v uint64
+		for j := i; j < i+width; j++ {
+			d := b.decodeMap[payload[j]]
+			if d == 0xFF {
+				return words, corruptInputError{offset: offset + j}
+			}
+			v = v*b.radix + uint64(d)
+		}
+
+		carry := v
+		for k := range words {
+			hi, lo := bits.Mul64(words[k], b.pow[width])
+			lo, c := bits.Add64(lo, carry, 0)
+			words[k] = lo
+			carry = hi + c
+		}
+		if carry != 0 {
+			words = append(words, carry)
+		}
+	}
+	return words, nil
+}
+
+// validate only checks the alphabet, which is linear unlike decoding
+func (b *bigBase) validate(src []byte) error {
+	if len(src) == 0 {
+		return corruptInputError{0, errInputLength}
+	}
+	for i, c := range src {
+		if b.decodeMap[c] == 0xFF {
+			return corruptInputError{offset: i}
+		}
+	}
+	return nil
+}
+
+// Large numbers are converted by divide and conquer: the digits are
+// split in two halves, on a power of the chunk size, that are converted
+// recursively and combined with a single multiplication or division by
+// math/big, whose algorithms are sub-quadratic. The word loops above
+// convert the leaves.
+
+// powers returns the powers radix^(digits<<k) needed to split numbers of
+// up to n digits
+func (b *bigBase) powers(n int) []*big.Int {
+	pows := []*big.Int{new(big.Int).SetUint64(b.pow[b.digits])}
+	for b.digits<<(len(pows)+1) <= n {
+		p := pows[len(pows)-1]
+		pows = append(pows, new(big.Int).Mul(p, p))
+	}
+	return pows
+}
+
+// split returns the largest k such that the n digits can be split at
+// m = digits<<k with m <= n/2
+func (b *bigBase) split(n int, pows []*big.Int) (k, m int) {
+	for k+1 < len(pows) && b.digits<<(k+2) <= n {
+		k++
+	}
+	return k, b.digits << k
+}
+
+// encodeBig writes the digits of x to out, padded with zero digits; x
+// must be lower than radix^len(out)
+func (b *bigBase) encodeBig(out []byte, x *big.Int, pows []*big.Int) {
+	if x.BitLen() <= 64*bigBaseSmallWords {
+		var buf [8 * bigBaseSmallWords]byte
+		var words [bigBaseSmallWords]uint64
+		loadWords(words[:], x.FillBytes(buf[:]))
+		pos := b.putDigits(out, words[:])
+		for i := 0; i < pos; i++ {
+			out[i] = b.alphabet[0]
+		}
+		return
+	}
+	k, m := b.split(len(out), pows)
+	q, r := new(big.Int).QuoRem(x, pows[k], new(big.Int))
+	b.encodeBig(out[:len(out)-m], q, pows)
+	b.encodeBig(out[len(out)-m:], r, pows)
+}
+
+// decodeBig returns the number made of the digits of payload, offset is
+// its position in the input for errors
+func (b *bigBase) decodeBig(payload []byte, offset int, pows []*big.Int) (*big.Int, error) {
+	if len(payload) <= b.digits*bigBaseSmallWords {
+		var small [bigBaseSmallWords + 1]uint64
+		words, err := b.addDigits(small[:0], payload, offset)
+		if err != nil {
+			return nil, err
+		}
+		var buf [8 * (bigBaseSmallWords + 1)]byte
+		for i, w := range words {
+			binary.BigEndian.PutUint64(buf[len(buf)-8*(i+1):], w)
+		}
+		return new(big.Int).SetBytes(buf[len(buf)-8*len(words):]), nil
+	}
+	k, m := b.split(len(payload), pows)
+	hi, err := b.decodeBig(payload[:len(payload)-m], offset, pows)
+	if err != nil {
+		return nil, err
+	}
+	lo, err := b.decodeBig(payload[len(payload)-m:], offset+len(payload)-m, pows)
+	if err != nil {
+		return nil, err
+	}
+	return hi.Mul(hi, pows[k]).Add(hi, lo), nil
+}
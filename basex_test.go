@@ -0,0 +1,168 @@
+package multibase
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+func TestBase10Vectors(t *testing.T) {
+	vectors := []struct {
+		data    string
+		encoded string
+	}{
+		{"", "9"},
+		{"\x00", "90"},
+		{"\x00\x00", "900"},
+		{"\x01", "91"},
+		{"\x00\x01", "901"},
+		{"\xff", "9255"},
+		{"\x01\x00", "9256"},
+		{"yes mani !", "9573277761329450583662625"},
+		{"\x00yes mani !", "90573277761329450583662625"},
+		{"\x00\x00yes mani !", "900573277761329450583662625"},
+	}
+	for _, v := range vectors {
+		testEncode(t, Base10, []byte(v.data), v.encoded)
+		if v.data == "" {
+			// like base36 and base58, an empty number doesn't decode
+			if _, _, err := Decode(v.encoded); err == nil {
+				t.Errorf("decoding %q should fail", v.encoded)
+			}
+			continue
+		}
+		testDecode(t, Base10, []byte(v.data), v.encoded)
+	}
+}
+
+// TestBigBaseLarge checks that the divide and conquer conversions give
+// the same results as the word loops
+func TestBigBaseLarge(t *testing.T) {
+	defer func(n int) { bigBaseThreshold = n }(bigBaseThreshold)
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	for _, n := range []int{0, 1, 127, 128, 129, 1000, 1024, 4097, 20000} {
+		for _, zeros := range []int{0, 3} {
+			data := make([]byte, zeros+n)
+			for i := zeros; i < len(data); i++ {
+				data[i] = byte(rng.Uint32())
+			}
+			if n > 0 {
+				data[zeros] |= 1
+			}
+			// a run of zero bytes inside the number
+			if n > 600 {
+				clear(data[zeros+300 : zeros+600])
+			}
+
+			for base := range bigNumberEncodings {
+				bigBaseThreshold = 1 << 30
+				expected, err := Encode(base, data)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				bigBaseThreshold = 1
+				encoded, err := Encode(base, data)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if encoded != expected {
+					t.Fatalf("%s: encoding of %d bytes differs", base, len(data))
+				}
+				if len(data) == 0 {
+					continue // the empty number doesn't decode
+				}
+				_, decoded, err := Decode(encoded)
+				if err != nil {
+					t.Fatalf("%s: decoding of %d bytes failed: %v", base, len(data), err)
+				}
+				if !bytes.Equal(decoded, data) {
+					t.Fatalf("%s: decoding of %d bytes differs", base, len(data))
+				}
+			}
+		}
+	}
+}
+
+// TestBigBaseLargeRoundTrip transcodes a large payload with the default
+// options
+func TestBigBaseLargeRoundTrip(t *testing.T) {
+	data := make([]byte, 100<<10)
+	rng := rand.New(rand.NewPCG(3, 4))
+	for i := range data {
+		data[i] = byte(rng.Uint32())
+	}
+	for _, base := range []Encoding{Base58BTC, Base36} {
+		encoded, err := Encode(base, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, decoded, err := Decode(encoded)
+		if err != nil {
+			t.Fatalf("%s: decoding %d bytes failed: %v", base, len(encoded), err)
+		}
+		if !bytes.Equal(decoded, data) {
+			t.Fatalf("%s: round trip of %d bytes differs", base, len(data))
+		}
+	}
+}
+
+func TestBigBaseLargeCorrupt(t *testing.T) {
+	defer func(n int) { bigBaseThreshold = n }(bigBaseThreshold)
+	bigBaseThreshold = 1
+
+	encoded := []byte(strings.Repeat("z", 5000))
+	for _, offset := range []int{0, 17, 2500, 4999} {
+		src := bytes.Clone(encoded)
+		src[offset] = '0'
+		_, err := base58BTCCodec.AppendDecode(nil, src)
+		var ce corruptInputError
+		if !errors.As(err, &ce) || ce.offset != offset {
+			t.Errorf("expected a corrupt input at %d, got %v", offset, err)
+		}
+	}
+}
+
+// BenchmarkBigBase compares the word loops with the divide and conquer
+// conversions on base58btc
+func BenchmarkBigBase(b *testing.B) {
+	defer func(n int) { bigBaseThreshold = n }(bigBaseThreshold)
+
+	for _, size := range []int{256, 1 << 10, 4 << 10, 16 << 10, 64 << 10} {
+		data := make([]byte, size)
+		rng := rand.New(rand.NewPCG(1, 2))
+		for i := range data {
+			data[i] = byte(rng.Uint32())
+		}
+		data[0] |= 1
+		encoded := base58BTCCodec.AppendEncode(nil, data)
+
+		for _, mode := range []struct {
+			name      string
+			threshold int
+		}{{"words", 1 << 30}, {"split", 1}} {
+			b.Run(fmt.Sprintf("Encode/%s/%d", mode.name, size), func(b *testing.B) {
+				bigBaseThreshold = mode.threshold
+				dst := make([]byte, 0, len(encoded))
+				b.SetBytes(int64(size))
+				for i := 0; i < b.N; i++ {
+					base58BTCCodec.AppendEncode(dst[:0], data)
+				}
+			})
+			b.Run(fmt.Sprintf("Decode/%s/%d", mode.name, size), func(b *testing.B) {
+				bigBaseThreshold = mode.threshold
+				dst := make([]byte, 0, size)
+				b.SetBytes(int64(size))
+				for i := 0; i < b.N; i++ {
+					if _, err := base58BTCCodec.AppendDecode(dst[:0], encoded); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
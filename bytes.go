@@ -0,0 +1,64 @@
+package multibase
+
+// EncodingType selects the encoding of EncodedBytes, its zero value must
+// return the encoding. Types for the common encodings are provided, like
+// AsBase58BTC, other ones can be declared the same way.
+type EncodingType interface {
+	Encoding() Encoding
+}
+
+// Encoding types for EncodedBytes.
+type (
+	AsBase16       struct{}
+	AsBase32       struct{}
+	AsBase36       struct{}
+	AsBase58BTC    struct{}
+	AsBase64       struct{}
+	AsBase64url    struct{}
+	AsBase256Emoji struct{}
+)
+
+func (AsBase16) Encoding() Encoding       { return Base16 }
+func (AsBase32) Encoding() Encoding       { return Base32 }
+func (AsBase36) Encoding() Encoding       { return Base36 }
+func (AsBase58BTC) Encoding() Encoding    { return Base58BTC }
+func (AsBase64) Encoding() Encoding       { return Base64 }
+func (AsBase64url) Encoding() Encoding    { return Base64url }
+func (AsBase256Emoji) Encoding() Encoding { return Base256Emoji }
+
+// EncodedBytes is a byte slice represented as a multibase string in text
+// formats like JSON: it is marshalled with the encoding selected by E,
+// and unmarshalled from any supported encoding.
+type EncodedBytes[E EncodingType] []byte
+
+// Bytes is a byte slice marshalled as a base64url multibase string.
+type Bytes = EncodedBytes[AsBase64url]
+
+// MarshalText implements encoding.TextMarshaler.
+func (b EncodedBytes[E]) MarshalText() ([]byte, error) {
+	return b.AppendText(nil)
+}
+
+// AppendText implements encoding.TextAppender.
+func (b EncodedBytes[E]) AppendText(dst []byte) ([]byte, error) {
+	var e E
+	return AppendEncode(dst, e.Encoding(), b)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, text can use any
+// supported encoding.
+func (b *EncodedBytes[E]) UnmarshalText(text []byte) error {
+	_, out, err := DecodeBytes(text)
+	if err != nil {
+		return err
+	}
+	*b = out
+	return nil
+}
+
+// String returns the multibase string of b.
+func (b EncodedBytes[E]) String() string {
+	var e E
+	s, _ := Encode(e.Encoding(), b)
+	return s
+}
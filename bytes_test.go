@@ -0,0 +1,75 @@
+package multibase
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"testing"
+)
+
+var (
+	_ encoding.TextMarshaler   = Bytes{}
+	_ encoding.TextAppender    = Bytes{}
+	_ encoding.TextUnmarshaler = (*Bytes)(nil)
+)
+
+func TestBytesJSON(t *testing.T) {
+	type record struct {
+		Key  Bytes                     `json:"key"`
+		Hash EncodedBytes[AsBase58BTC] `json:"hash"`
+	}
+
+	r := record{Key: Bytes("hello"), Hash: EncodedBytes[AsBase58BTC]("world")}
+	out, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"key":"uaGVsbG8","hash":"zEUYUqQf"}` {
+		t.Errorf("unexpected json %s", out)
+	}
+
+	var r2 record
+	if err := json.Unmarshal(out, &r2); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(r2.Key, r.Key) || !bytes.Equal(r2.Hash, r.Hash) {
+		t.Errorf("round trip failed, got %q %q", r2.Key, r2.Hash)
+	}
+
+	// any encoding is accepted when unmarshalling
+	if err := json.Unmarshal([]byte(`{"key":"f68656c6c6f","hash":"bo5xxe3de"}`), &r2); err != nil {
+		t.Fatal(err)
+	}
+	if string(r2.Key) != "hello" || string(r2.Hash) != "world" {
+		t.Errorf("unexpected decoding %q %q", r2.Key, r2.Hash)
+	}
+
+	for _, data := range []string{`{"key":""}`, `{"key":"q123"}`, `{"key":"f0g"}`} {
+		if err := json.Unmarshal([]byte(data), &r2); err == nil {
+			t.Errorf("%s: expected an error", data)
+		}
+	}
+}
+
+func TestBytesText(t *testing.T) {
+	b := EncodedBytes[AsBase32]("foo")
+	out, err := b.AppendText([]byte("x="))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "x=bmzxw6" {
+		t.Errorf("unexpected AppendText result %q", out)
+	}
+	if b.String() != "bmzxw6" {
+		t.Errorf("unexpected String result %q", b.String())
+	}
+
+	var empty Bytes
+	out, err = empty.MarshalText()
+	if err != nil || string(out) != "u" {
+		t.Errorf("unexpected marshalling of empty bytes %q, %v", out, err)
+	}
+	if err := empty.UnmarshalText(out); err != nil || len(empty) != 0 {
+		t.Errorf("unexpected unmarshalling of empty bytes %q, %v", empty, err)
+	}
+}
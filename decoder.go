@@ -0,0 +1,58 @@
+package multibase
+
+import (
+	"fmt"
+)
+
+// Decoder decodes multibase strings using one of a set of verified
+// encodings, any other prefix is rejected with a *DecodeError of kind
+// ErrEncodingNotAllowed. The zero value rejects everything.
+type Decoder struct {
+	allowed []Encoding
+}
+
+// NewDecoder creates a new Decoder accepting the given encodings
+func NewDecoder(allowed ...Encoding) (Decoder, error) {
+	if len(allowed) == 0 {
+		return Decoder{}, fmt.Errorf("no multibase encoding allowed")
+	}
+	for _, base := range allowed {
+		if _, ok := lookupCodec(base); !ok {
+			return Decoder{}, fmt.Errorf("unsupported multibase encoding: %d", base)
+		}
+	}
+	return Decoder{append([]Encoding(nil), allowed...)}, nil
+}
+
+// MustNewDecoder is like NewDecoder but will panic if an encoding is
+// invalid.
+func MustNewDecoder(allowed ...Encoding) Decoder {
+	d, err := NewDecoder(allowed...)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// Decode decodes the multibase string data if its encoding is allowed,
+// within the limits of DefaultDecodeOptions.
+func (d Decoder) Decode(data string) (Encoding, []byte, error) {
+	enc, out, err := DefaultDecodeOptions.appendDecode(nil, stringBytes(data), d.list())
+	if err != nil {
+		return enc, nil, err
+	}
+	return enc, out, nil
+}
+
+// Validate is like Decode but only checks data, see Validate.
+func (d Decoder) Validate(data string) (Encoding, error) {
+	return validate(stringBytes(data), d.list())
+}
+
+// list returns the allowed encodings, never nil as nil allows everything
+func (d Decoder) list() []Encoding {
+	if d.allowed == nil {
+		return []Encoding{}
+	}
+	return d.allowed
+}
@@ -0,0 +1,68 @@
+package multibase
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestDecoder(t *testing.T) {
+	d, err := NewDecoder(Base32, Base36)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, encoding := range []Encoding{Base32, Base36} {
+		e, out, err := d.Decode(encodedSamples[encoding])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e != encoding || !bytes.Equal(out, sampleBytes) {
+			t.Errorf("unexpected decoding %v %q", e, out)
+		}
+		if _, err := d.Validate(encodedSamples[encoding]); err != nil {
+			t.Error(err)
+		}
+	}
+
+	for _, encoding := range []Encoding{Identity, Base32Upper, Base58BTC, Base256Emoji} {
+		e, out, err := d.Decode(encodedSamples[encoding])
+		var derr *DecodeError
+		if !errors.As(err, &derr) || derr.Kind != ErrEncodingNotAllowed || e != encoding || out != nil {
+			t.Errorf("decoding %v should not be allowed, got %v", encoding, err)
+		}
+		if _, err := d.Validate(encodedSamples[encoding]); !errors.Is(err, ErrEncodingNotAllowed) {
+			t.Errorf("validating %v should not be allowed, got %v", encoding, err)
+		}
+	}
+
+	if _, _, err := d.Decode("b!"); !errors.Is(err, ErrCorruptPayload) {
+		t.Errorf("expected ErrCorruptPayload, got %v", err)
+	}
+	if _, _, err := d.Decode(""); !errors.Is(err, ErrEmptyInput) {
+		t.Errorf("expected ErrEmptyInput, got %v", err)
+	}
+}
+
+func TestNewDecoderInvalid(t *testing.T) {
+	if _, err := NewDecoder(); err == nil {
+		t.Error("NewDecoder should fail without encodings")
+	}
+	if _, err := NewDecoder(Base32, 'q'); err == nil {
+		t.Error("NewDecoder should fail with an unsupported encoding")
+	}
+
+	var d Decoder
+	if _, _, err := d.Decode(encodedSamples[Base32]); !errors.Is(err, ErrEncodingNotAllowed) {
+		t.Errorf("the zero Decoder should reject everything, got %v", err)
+	}
+	if _, err := d.Validate(encodedSamples[Base32]); !errors.Is(err, ErrEncodingNotAllowed) {
+		t.Errorf("the zero Decoder should reject everything, got %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustNewDecoder should panic")
+		}
+	}()
+	MustNewDecoder('q')
+}
@@ -13,7 +13,7 @@ type Encoder struct {
 
 // NewEncoder create a new Encoder from an Encoding
 func NewEncoder(base Encoding) (Encoder, error) {
-	_, ok := EncodingToStr[base]
+	_, ok := lookupCodec(base)
 	if !ok {
 		return Encoder{-1}, fmt.Errorf("unsupported multibase encoding: %d", base)
 	}
@@ -23,7 +23,7 @@ func NewEncoder(base Encoding) (Encoder, error) {
 // MustNewEncoder is like NewEncoder but will panic if the encoding is
 // invalid.
 func MustNewEncoder(base Encoding) Encoder {
-	_, ok := EncodingToStr[base]
+	_, ok := lookupCodec(base)
 	if !ok {
 		panic("Unsupported multibase encoding")
 	}
@@ -40,9 +40,11 @@ func EncoderByName(str string) (Encoder, error) {
 	} else if utf8.RuneCountInString(str) == 1 {
 		r, _ := utf8.DecodeRuneInString(str)
 		base = Encoding(r)
-		_, ok = EncodingToStr[base]
+		_, ok = lookupCodec(base)
 	} else {
-		base, ok = Encodings[str]
+		var info EncodingInfo
+		info, ok = LookupName(str)
+		base = info.Encoding
 	}
 	if !ok {
 		return Encoder{-1}, fmt.Errorf("unsupported multibase encoding: %s", str)
@@ -0,0 +1,154 @@
+package multibase
+
+import (
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Kinds of DecodeError, to be tested with errors.Is.
+var (
+	// ErrEmptyInput is returned when decoding an empty string, which
+	// doesn't even have a prefix.
+	ErrEmptyInput = fmt.Errorf("cannot decode multibase for zero length string")
+	// ErrUnknownPrefix is returned when the prefix isn't the one of an
+	// encoding known by this package.
+	ErrUnknownPrefix = fmt.Errorf("unknown multibase prefix")
+	// ErrReservedPrefix is returned when the prefix is reserved by the
+	// multibase specification and will never select an encoding.
+	ErrReservedPrefix = fmt.Errorf("reserved multibase prefix")
+	// ErrCorruptPayload is returned when the data following the prefix
+	// is not valid for its encoding.
+	ErrCorruptPayload = fmt.Errorf("corrupt multibase payload")
+	// ErrEncodingNotAllowed is returned when the prefix selects an
+	// encoding that the caller doesn't accept.
+	ErrEncodingNotAllowed = fmt.Errorf("multibase encoding not allowed")
+	// ErrInputTooLarge is returned when the multibase string, or the
+	// data it decodes to, is over the limits of DecodeOptions.
+	ErrInputTooLarge = fmt.Errorf("multibase input too large")
+)
+
+// DecodeError is the error returned when a multibase string can't be
+// decoded. errors.Is matches it with its Kind, and for compatibility
+// with ErrUnsupportedEncoding when the prefix is unknown or reserved.
+type DecodeError struct {
+	// Encoding is the encoding selected by the prefix, -1 when the
+	// prefix is unknown or reserved and 0 for an empty input
+	Encoding Encoding
+	// Offset is the byte offset of the error in the multibase string,
+	// prefix included
+	Offset int
+	// Rune is the offending character, -1 when the error isn't caused
+	// by a character, like a truncated input
+	Rune rune
+	// Kind is the reason of the failure: ErrEmptyInput,
+	// ErrUnknownPrefix, ErrReservedPrefix, ErrEncodingNotAllowed,
+	// ErrInputTooLarge, ErrCorruptPayload, or one of the strict decoding
+	// errors like ErrWrongCase
+	Kind error
+	// Err gives more details about a corrupt payload, it may be nil
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	switch e.Kind {
+	case ErrEmptyInput:
+		return e.Kind.Error()
+	case ErrUnknownPrefix, ErrReservedPrefix:
+		return e.Kind.Error() + " " + strconv.QuoteRune(e.Rune)
+	case ErrEncodingNotAllowed:
+		return e.Kind.Error() + ": " + e.Encoding.String()
+	case ErrInputTooLarge:
+		return e.Kind.Error() + ": " + e.Encoding.String() + " " + e.Err.Error()
+	}
+
+	msg := "illegal "
+	if e.Kind != ErrCorruptPayload {
+		msg = "non-canonical "
+	}
+	msg += e.Encoding.String() + " data at input byte " + strconv.Itoa(e.Offset)
+	if e.Kind != ErrCorruptPayload {
+		msg += ": " + e.Kind.Error()
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *DecodeError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// Is reports unknown and reserved prefixes as ErrUnsupportedEncoding,
+// which Decode used to return for both.
+func (e *DecodeError) Is(target error) bool {
+	return target == ErrUnsupportedEncoding && (e.Kind == ErrUnknownPrefix || e.Kind == ErrReservedPrefix)
+}
+
+// prefixError returns the error of a prefix that doesn't select a
+// supported encoding
+func prefixError(src []byte) *DecodeError {
+	if len(src) == 0 {
+		return &DecodeError{Rune: -1, Kind: ErrEmptyInput}
+	}
+	r, _ := utf8.DecodeRune(src)
+	kind := ErrUnknownPrefix
+	if _, ok := reservedInfos[Encoding(r)]; ok {
+		kind = ErrReservedPrefix
+	}
+	return &DecodeError{Encoding: -1, Rune: r, Kind: kind}
+}
+
+// allowedError returns the error of a prefix selecting enc, which isn't
+// part of allowed
+func allowedError(enc Encoding, allowed []Encoding) error {
+	for _, a := range allowed {
+		if a == enc {
+			return nil
+		}
+	}
+	return &DecodeError{Encoding: enc, Rune: rune(enc), Kind: ErrEncodingNotAllowed}
+}
+
+// payloadError turns the error returned by the codec of enc decoding
+// src, the multibase string without its prefix of n bytes, into a
+// *DecodeError
+func payloadError(enc Encoding, n int, src []byte, err error) *DecodeError {
+	e := &DecodeError{Encoding: enc, Offset: n, Rune: -1, Kind: ErrCorruptPayload, Err: err}
+	if cerr, ok := err.(corruptInputError); ok {
+		e.Offset += cerr.offset
+		e.Err = cerr.err
+		if cerr.offset < len(src) {
+			e.Rune, _ = utf8.DecodeRune(src[cerr.offset:])
+		}
+	}
+	return e
+}
+
+// Details of corruptInputError shared by the codecs.
+var (
+	errInputLength = fmt.Errorf("invalid input length")
+	errPadding     = fmt.Errorf("incorrect padding")
+)
+
+// corruptInputError is returned by the codecs for malformed input,
+// offset is relative to the data following the multibase prefix, it is
+// the length of the data when the input is truncated
+type corruptInputError struct {
+	offset int
+	// err tells what is wrong when the offending character isn't
+	// enough, it may be nil
+	err error
+}
+
+func (e corruptInputError) Error() string {
+	msg := "illegal data at input byte " + strconv.Itoa(e.offset)
+	if e.err != nil {
+		msg += ": " + e.err.Error()
+	}
+	return msg
+}
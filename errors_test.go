@@ -0,0 +1,96 @@
+package multibase
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecodeErrorPrefix(t *testing.T) {
+	for _, tc := range []struct {
+		data string
+		kind error
+		r    rune
+	}{
+		{"", ErrEmptyInput, -1},
+		{"q1", ErrUnknownPrefix, 'q'},
+		{"é", ErrUnknownPrefix, 'é'},
+		{"Qmfoo", ErrReservedPrefix, 'Q'},
+		{"1foo", ErrReservedPrefix, '1'},
+		{"/ipfs", ErrReservedPrefix, '/'},
+	} {
+		_, _, err := Decode(tc.data)
+		var derr *DecodeError
+		if !errors.As(err, &derr) {
+			t.Errorf("Decode(%q): expected a DecodeError, got %v", tc.data, err)
+			continue
+		}
+		if !errors.Is(err, tc.kind) || derr.Rune != tc.r || derr.Offset != 0 {
+			t.Errorf("Decode(%q): unexpected error %#v", tc.data, derr)
+		}
+		if unsupported := tc.kind != ErrEmptyInput; errors.Is(err, ErrUnsupportedEncoding) != unsupported {
+			t.Errorf("Decode(%q): errors.Is(%v, ErrUnsupportedEncoding) should be %v", tc.data, err, unsupported)
+		}
+	}
+}
+
+func TestDecodeErrorPayload(t *testing.T) {
+	for encoding, sample := range encodedSamples {
+		if encoding == Identity || encoding == Base256Emoji {
+			continue
+		}
+		corrupt := sample[:5] + "!" + sample[6:]
+		_, _, err := Decode(corrupt)
+		var derr *DecodeError
+		if !errors.As(err, &derr) || !errors.Is(err, ErrCorruptPayload) {
+			t.Errorf("Decode(%q): expected a corrupt payload, got %v", corrupt, err)
+			continue
+		}
+		if derr.Encoding != encoding || derr.Offset != 5 || derr.Rune != '!' {
+			t.Errorf("Decode(%q): unexpected error %#v", corrupt, derr)
+		}
+	}
+
+	for _, tc := range []struct {
+		data   string
+		offset int
+		r      rune
+		err    error
+	}{
+		{"\U0001F680\U0001F680a", 8, 'a', nil},
+		{"f001", 4, -1, errInputLength},
+		{"R1", 2, -1, errInputLength},
+		{"RGGW", 1, 'G', errBase45Range},
+		{"cmzxw6=", 7, -1, errPadding},
+		{"zI", 1, 'I', nil},
+	} {
+		_, _, err := Decode(tc.data)
+		var derr *DecodeError
+		if !errors.As(err, &derr) || !errors.Is(err, ErrCorruptPayload) {
+			t.Errorf("Decode(%q): expected a corrupt payload, got %v", tc.data, err)
+			continue
+		}
+		if derr.Offset != tc.offset || derr.Rune != tc.r || derr.Err != tc.err {
+			t.Errorf("Decode(%q): unexpected error %#v", tc.data, derr)
+		}
+	}
+}
+
+func TestDecodeErrorMessage(t *testing.T) {
+	for _, tc := range []struct {
+		data, msg string
+	}{
+		{"", "cannot decode multibase for zero length string"},
+		{"q1", "unknown multibase prefix 'q'"},
+		{"Qm", "reserved multibase prefix 'Q'"},
+		{"f0g", "illegal base16 data at input byte 2"},
+		{"RGGW", "illegal base45 data at input byte 1: value out of range"},
+	} {
+		if _, _, err := Decode(tc.data); err == nil || err.Error() != tc.msg {
+			t.Errorf("Decode(%q): expected %q, got %v", tc.data, tc.msg, err)
+		}
+	}
+
+	if _, _, err := DecodeStrict("bMZXW6"); err == nil || err.Error() != "non-canonical base32 data at input byte 1: data case does not match the encoding" {
+		t.Errorf("unexpected strict error %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package multibase
+
+import (
+	"strings"
+)
+
+// Status is the status of an encoding in the multibase specification.
+type Status int
+
+const (
+	// StatusUnknown is the status of the encodings that are not in the
+	// multibase table, like the ones added with Register.
+	StatusUnknown Status = iota
+	// StatusReserved is the status of the prefixes that the
+	// specification keeps out of use.
+	StatusReserved
+	// StatusDraft is the status of the encodings that may still change.
+	StatusDraft
+	// StatusFinal is the status of the encodings that are stable.
+	StatusFinal
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusReserved:
+		return "reserved"
+	case StatusDraft:
+		return "draft"
+	case StatusFinal:
+		return "final"
+	}
+	return "unknown"
+}
+
+// EncodingInfo describes an encoding.
+type EncodingInfo struct {
+	// Encoding is the prefix of the encoding
+	Encoding Encoding
+	// Name is the name of the encoding in the multibase table
+	Name string
+	// Alphabet holds the characters the encoded data is made of, in the
+	// order of their value. For proquint, it is the consonants followed
+	// by the vowels. It is empty for identity and unknown encodings.
+	Alphabet string
+	// Padding is the character completing the last block, 0 when the
+	// encoding isn't padded
+	Padding rune
+	// CaseInsensitive is set when decoding ignores the case of the
+	// data, or when the alphabet has no letters
+	CaseInsensitive bool
+	// BitsPerSymbol is the number of bits every character of the
+	// alphabet carries, on average over a block. It is 0 for the
+	// big-number encodings.
+	BitsPerSymbol float64
+	// BigNumber is set for the encodings that convert the whole data as
+	// a single number, like base58, where the length of the output
+	// depends on the value of the data.
+	BigNumber bool
+	// URLSafe is set when the encoded data only uses the unreserved
+	// characters of URLs (RFC 3986), which are never escaped.
+	URLSafe bool
+	// FilenameSafe is set when the encoded data only uses the portable
+	// filename characters of POSIX.
+	FilenameSafe bool
+	// DNSSafe is set when the encoded data can be used in a DNS label:
+	// letters, digits and hyphens, with case insensitive decoding.
+	DNSSafe bool
+	// Status is the status of the encoding in the specification
+	Status Status
+}
+
+// reservedInfos describes the prefixes reserved by the specification:
+// '1' and 'Q' start base58btc CIDv0 and peer IDs, '/' starts paths.
+var reservedInfos = map[Encoding]EncodingInfo{
+	'1': {Encoding: '1', Name: "none", Status: StatusReserved},
+	'Q': {Encoding: 'Q', Name: "none", Status: StatusReserved},
+	'/': {Encoding: '/', Name: "none", Status: StatusReserved},
+}
+
+// Info describes the encoding e. It covers the registered encodings as
+// well as the prefixes reserved by the specification. For other
+// prefixes, only the Encoding field is set.
+func (e Encoding) Info() EncodingInfo {
+	if info, ok := Lookup(e); ok {
+		return info
+	}
+	if info, ok := reservedInfos[e]; ok {
+		return info
+	}
+	return EncodingInfo{Encoding: e}
+}
+
+// setSafety sets the URLSafe, FilenameSafe and DNSSafe flags according
+// to the alphabet and padding
+func (info *EncodingInfo) setSafety() {
+	if info.Alphabet == "" {
+		return
+	}
+	symbols := info.Alphabet
+	if info.Padding != 0 {
+		symbols += string(info.Padding)
+	}
+	if info.Encoding == Proquint {
+		symbols += "-"
+	}
+
+	const alnum = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+	only := func(set string) bool {
+		return !strings.ContainsFunc(symbols, func(r rune) bool {
+			return !strings.ContainsRune(set, r)
+		})
+	}
+	info.URLSafe = only(alnum + "-._~")
+	info.FilenameSafe = only(alnum + "-._")
+	info.DNSSafe = info.CaseInsensitive && only(alnum+"-")
+}
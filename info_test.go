@@ -0,0 +1,84 @@
+package multibase
+
+import (
+	"bytes"
+	"crypto/rand"
+	"strings"
+	"testing"
+)
+
+func TestInfo(t *testing.T) {
+	for _, tc := range []EncodingInfo{
+		{Encoding: Base16, Name: "base16", CaseInsensitive: true, BitsPerSymbol: 4, URLSafe: true, FilenameSafe: true, DNSSafe: true, Status: StatusFinal},
+		{Encoding: Base32pad, Name: "base32pad", Padding: '=', CaseInsensitive: true, BitsPerSymbol: 5, Status: StatusFinal},
+		{Encoding: Base36, Name: "base36", CaseInsensitive: true, BigNumber: true, URLSafe: true, FilenameSafe: true, DNSSafe: true, Status: StatusDraft},
+		{Encoding: Base58BTC, Name: "base58btc", BigNumber: true, URLSafe: true, FilenameSafe: true, Status: StatusFinal},
+		{Encoding: Base64, Name: "base64", BitsPerSymbol: 6, Status: StatusFinal},
+		{Encoding: Base64url, Name: "base64url", BitsPerSymbol: 6, URLSafe: true, FilenameSafe: true, Status: StatusFinal},
+		{Encoding: Proquint, Name: "proquint", BitsPerSymbol: 3.2, URLSafe: true, FilenameSafe: true, Status: StatusDraft},
+		{Encoding: Base256Emoji, Name: "base256emoji", BitsPerSymbol: 8, Status: StatusDraft},
+		{Encoding: 'Q', Name: "none", Status: StatusReserved},
+		{Encoding: 'q'},
+	} {
+		info := tc.Encoding.Info()
+		info.Alphabet = ""
+		if info != tc {
+			t.Errorf("unexpected info for %q:\n%+v\nexpected\n%+v", rune(tc.Encoding), info, tc)
+		}
+	}
+
+	if a := Encoding(Base58Flickr).Info().Alphabet; a != "123456789abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ" {
+		t.Errorf("unexpected base58flickr alphabet %q", a)
+	}
+}
+
+func TestInfoAlphabet(t *testing.T) {
+	data := make([]byte, 100)
+	rand.Read(data)
+	for _, info := range All() {
+		if info.Alphabet == "" {
+			continue
+		}
+		encoded, err := Encode(info.Encoding, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		payload := strings.TrimPrefix(encoded, string(rune(info.Encoding)))
+		if info.Encoding == Proquint {
+			payload = strings.ReplaceAll(strings.TrimPrefix(payload, "ro-"), "-", "")
+		}
+		if info.Padding != 0 {
+			payload = strings.TrimRight(payload, string(info.Padding))
+		}
+		for _, r := range payload {
+			if !strings.ContainsRune(info.Alphabet, r) {
+				t.Errorf("%s: %q is not in the alphabet", info.Name, r)
+				break
+			}
+		}
+
+		if info.CaseInsensitive {
+			prefix := string(rune(info.Encoding))
+			padding := encoded[len(prefix)+len(payload):]
+			for _, s := range []string{strings.ToLower(payload), strings.ToUpper(payload)} {
+				_, out, err := Decode(prefix + s + padding)
+				if err != nil || !bytes.Equal(out, data) {
+					t.Errorf("%s is not case insensitive: %v", info.Name, err)
+				}
+			}
+		}
+	}
+}
+
+func TestStatusString(t *testing.T) {
+	for s, str := range map[Status]string{
+		StatusUnknown:  "unknown",
+		StatusReserved: "reserved",
+		StatusDraft:    "draft",
+		StatusFinal:    "final",
+	} {
+		if s.String() != str {
+			t.Errorf("expected %q, got %q", str, s.String())
+		}
+	}
+}
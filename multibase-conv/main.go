@@ -13,11 +13,11 @@ func main() {
 		os.Exit(1)
 	}
 
-	var newBase multibase.Encoding
-	if baseParam := os.Args[1]; len(baseParam) != 0 {
-		newBase = multibase.Encoding(baseParam[0])
-	} else {
-		fmt.Fprintln(os.Stderr, "<new-base> is empty")
+	// <new-base> can either be the multibase name (e.g. base45) or its
+	// prefix character
+	newBase, err := multibase.EncoderByName(os.Args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid <new-base>: %s\n", err)
 		os.Exit(1)
 	}
 
@@ -30,12 +30,7 @@ func main() {
 			os.Exit(1)
 		}
 
-		newCid, err := multibase.Encode(newBase, data)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "error while encoding: %s\n", err)
-			os.Exit(1)
-		}
-		fmt.Println(newCid)
+		fmt.Println(newBase.Encode(data))
 	}
 
 }
@@ -1,14 +1,10 @@
 package multibase
 
 import (
-	"encoding/base64"
-	"encoding/hex"
 	"fmt"
+	"io"
 	"unicode/utf8"
-
-	b58 "github.com/mr-tron/base58"
-	b32 "github.com/multiformats/go-base32"
-	b36 "github.com/multiformats/go-base36"
+	"unsafe"
 )
 
 // Encoding identifies the type of base-encoding that a multibase is carrying.
@@ -31,6 +27,7 @@ const (
 	Base32hexUpper    = 'V'
 	Base32hexPad      = 't'
 	Base32hexPadUpper = 'T'
+	Base32z           = 'h'
 	Base36            = 'k'
 	Base36Upper       = 'K'
 	Base45            = 'R'
@@ -40,156 +37,253 @@ const (
 	Base64url         = 'u'
 	Base64pad         = 'M'
 	Base64urlPad      = 'U'
+	Proquint          = 'p'
 	Base256Emoji      = '🚀'
 )
 
-// EncodingToStr is a map of the supported encoding, unsupported encoding
-// specified in standard are left out
-var EncodingToStr = map[Encoding]string{
-	0x00:         "identity",
-	'0':          "base2",
-	'f':          "base16",
-	'F':          "base16upper",
-	'b':          "base32",
-	'B':          "base32upper",
-	'c':          "base32pad",
-	'C':          "base32padupper",
-	'v':          "base32hex",
-	'V':          "base32hexupper",
-	't':          "base32hexpad",
-	'T':          "base32hexpadupper",
-	'k':          "base36",
-	'K':          "base36upper",
-	'z':          "base58btc",
-	'Z':          "base58flickr",
-	'm':          "base64",
-	'u':          "base64url",
-	'M':          "base64pad",
-	'U':          "base64urlpad",
-	Base256Emoji: "base256emoji",
-}
+// EncodingToStr maps the builtin encodings to their names. It is kept
+// for compatibility as a snapshot taken when the package is initialized:
+// the encodings added with Register are not part of it and modifying it
+// has no effect on the package, use Lookup instead.
+var EncodingToStr = map[Encoding]string{}
 
+// Encodings maps the names of the builtin encodings to their prefix. Like
+// EncodingToStr, it is a snapshot taken when the package is initialized,
+// use LookupName instead.
 var Encodings = map[string]Encoding{}
 
-func init() {
-	for e, n := range EncodingToStr {
-		Encodings[n] = e
-	}
-}
-
 // ErrUnsupportedEncoding is returned when the selected encoding is not known or
-// implemented.
+// implemented. Decoding errors due to the prefix match it with errors.Is.
 var ErrUnsupportedEncoding = fmt.Errorf("selected encoding not supported")
 
+// Codec encodes and decodes the data of a multibase string, following
+// its prefix. The Append methods match the ones of the standard library
+// encodings, they must not modify src.
+type Codec interface {
+	AppendEncode(dst, src []byte) []byte
+	AppendDecode(dst, src []byte) ([]byte, error)
+	// EncodedLen returns the length of the encoding of n bytes, or its
+	// maximum when it depends on the data
+	EncodedLen(n int) int
+	// DecodedLen returns the bounds of the length of the decoding of
+	// src, which are equal when it only depends on the length of src
+	DecodedLen(src []byte) (min, max int)
+}
+
+// builtins are the encodings implemented by this package, they are
+// registered when the package is initialized
+var builtins = []struct {
+	info  EncodingInfo
+	codec Codec
+}{
+	{EncodingInfo{Encoding: Identity, Name: "identity", BitsPerSymbol: 8, Status: StatusFinal}, identityCodec{}},
+	{EncodingInfo{Encoding: Base2, Name: "base2", Alphabet: "01", CaseInsensitive: true, BitsPerSymbol: 1, Status: StatusFinal}, binaryCodec{}},
+	{EncodingInfo{Encoding: Base8, Name: "base8", Alphabet: "01234567", CaseInsensitive: true, BitsPerSymbol: 3, Status: StatusDraft}, octalCodec{}},
+	{EncodingInfo{Encoding: Base10, Name: "base10", Alphabet: base10Codec.alphabet, CaseInsensitive: true, BigNumber: true, Status: StatusDraft}, base10Codec},
+	{EncodingInfo{Encoding: Base16, Name: "base16", Alphabet: "0123456789abcdef", CaseInsensitive: true, BitsPerSymbol: 4, Status: StatusFinal}, hexCodec{}},
+	{EncodingInfo{Encoding: Base16Upper, Name: "base16upper", Alphabet: "0123456789ABCDEF", CaseInsensitive: true, BitsPerSymbol: 4, Status: StatusFinal}, hexCodec{upper: true}},
+	{EncodingInfo{Encoding: Base32hex, Name: "base32hex", Alphabet: base32HexLowerNoPad.Alphabet(), CaseInsensitive: true, BitsPerSymbol: 5, Status: StatusFinal}, newBase32Codec(base32HexLowerNoPad, false)},
+	{EncodingInfo{Encoding: Base32hexUpper, Name: "base32hexupper", Alphabet: base32HexUpperNoPad.Alphabet(), CaseInsensitive: true, BitsPerSymbol: 5, Status: StatusFinal}, newBase32Codec(base32HexUpperNoPad, false)},
+	{EncodingInfo{Encoding: Base32hexPad, Name: "base32hexpad", Alphabet: base32HexLowerPad.Alphabet(), Padding: '=', CaseInsensitive: true, BitsPerSymbol: 5, Status: StatusFinal}, newBase32Codec(base32HexLowerPad, true)},
+	{EncodingInfo{Encoding: Base32hexPadUpper, Name: "base32hexpadupper", Alphabet: base32HexUpperPad.Alphabet(), Padding: '=', CaseInsensitive: true, BitsPerSymbol: 5, Status: StatusFinal}, newBase32Codec(base32HexUpperPad, true)},
+	{EncodingInfo{Encoding: Base32, Name: "base32", Alphabet: base32StdLowerNoPad.Alphabet(), CaseInsensitive: true, BitsPerSymbol: 5, Status: StatusFinal}, newBase32Codec(base32StdLowerNoPad, false)},
+	{EncodingInfo{Encoding: Base32Upper, Name: "base32upper", Alphabet: base32StdUpperNoPad.Alphabet(), CaseInsensitive: true, BitsPerSymbol: 5, Status: StatusFinal}, newBase32Codec(base32StdUpperNoPad, false)},
+	{EncodingInfo{Encoding: Base32pad, Name: "base32pad", Alphabet: base32StdLowerPad.Alphabet(), Padding: '=', CaseInsensitive: true, BitsPerSymbol: 5, Status: StatusFinal}, newBase32Codec(base32StdLowerPad, true)},
+	{EncodingInfo{Encoding: Base32padUpper, Name: "base32padupper", Alphabet: base32StdUpperPad.Alphabet(), Padding: '=', CaseInsensitive: true, BitsPerSymbol: 5, Status: StatusFinal}, newBase32Codec(base32StdUpperPad, true)},
+	{EncodingInfo{Encoding: Base32z, Name: "base32z", Alphabet: base32ZNoPad.Alphabet(), CaseInsensitive: true, BitsPerSymbol: 5, Status: StatusDraft}, newBase32Codec(base32ZNoPad, false)},
+	{EncodingInfo{Encoding: Base36, Name: "base36", Alphabet: base36LowerCodec.alphabet, CaseInsensitive: true, BigNumber: true, Status: StatusDraft}, base36LowerCodec},
+	{EncodingInfo{Encoding: Base36Upper, Name: "base36upper", Alphabet: base36UpperCodec.alphabet, CaseInsensitive: true, BigNumber: true, Status: StatusDraft}, base36UpperCodec},
+	{EncodingInfo{Encoding: Base45, Name: "base45", Alphabet: base45Alphabet, BitsPerSymbol: 16.0 / 3, Status: StatusDraft}, base45Codec{}},
+	{EncodingInfo{Encoding: Base58BTC, Name: "base58btc", Alphabet: base58BTCCodec.alphabet, BigNumber: true, Status: StatusFinal}, base58BTCCodec},
+	{EncodingInfo{Encoding: Base58Flickr, Name: "base58flickr", Alphabet: base58FlickrCodec.alphabet, BigNumber: true, Status: StatusDraft}, base58FlickrCodec},
+	{EncodingInfo{Encoding: Base64, Name: "base64", Alphabet: base64Alphabet, BitsPerSymbol: 6, Status: StatusFinal}, newBase64Codec(base64Alphabet, false)},
+	{EncodingInfo{Encoding: Base64pad, Name: "base64pad", Alphabet: base64Alphabet, Padding: '=', BitsPerSymbol: 6, Status: StatusFinal}, newBase64Codec(base64Alphabet, true)},
+	{EncodingInfo{Encoding: Base64url, Name: "base64url", Alphabet: base64URLAlphabet, BitsPerSymbol: 6, Status: StatusFinal}, newBase64Codec(base64URLAlphabet, false)},
+	{EncodingInfo{Encoding: Base64urlPad, Name: "base64urlpad", Alphabet: base64URLAlphabet, Padding: '=', BitsPerSymbol: 6, Status: StatusFinal}, newBase64Codec(base64URLAlphabet, true)},
+	{EncodingInfo{Encoding: Proquint, Name: "proquint", Alphabet: proquintConsonants + proquintVowels, BitsPerSymbol: 16.0 / 5, Status: StatusDraft}, proquintCodec{}},
+	{EncodingInfo{Encoding: Base256Emoji, Name: "base256emoji", Alphabet: string(base256emojiTable[:]), BitsPerSymbol: 8, Status: StatusDraft}, base256emojiCodec{}},
+}
+
+// identityCodec copies the data as is. 0x00 inside a string is OK in
+// golang and causes no problems with the length calculation.
+type identityCodec struct{}
+
+func (identityCodec) AppendEncode(dst, src []byte) []byte {
+	return append(dst, src...)
+}
+
+func (identityCodec) AppendDecode(dst, src []byte) ([]byte, error) {
+	return append(dst, src...), nil
+}
+
+func (identityCodec) EncodedLen(n int) int {
+	return n
+}
+
+func (identityCodec) DecodedLen(src []byte) (min, max int) {
+	return len(src), len(src)
+}
+
+func (identityCodec) validate(src []byte) error {
+	return nil
+}
+
 // Encode encodes a given byte slice with the selected encoding and returns a
 // multibase string (<encoding><base-encoded-string>). It will return
 // an error if the selected base is not known.
 func Encode(base Encoding, data []byte) (string, error) {
-	switch base {
-	case Identity:
-		// 0x00 inside a string is OK in golang and causes no problems with the length calculation.
-		return string(rune(Identity)) + string(data), nil
-	case Base2:
-		return string(Base2) + binaryEncodeToString(data), nil
-	case Base16:
-		return string(Base16) + hex.EncodeToString(data), nil
-	case Base16Upper:
-		return string(Base16Upper) + hexEncodeToStringUpper(data), nil
-	case Base32:
-		return string(Base32) + base32StdLowerNoPad.EncodeToString(data), nil
-	case Base32Upper:
-		return string(Base32Upper) + base32StdUpperNoPad.EncodeToString(data), nil
-	case Base32hex:
-		return string(Base32hex) + base32HexLowerNoPad.EncodeToString(data), nil
-	case Base32hexUpper:
-		return string(Base32hexUpper) + base32HexUpperNoPad.EncodeToString(data), nil
-	case Base32pad:
-		return string(Base32pad) + base32StdLowerPad.EncodeToString(data), nil
-	case Base32padUpper:
-		return string(Base32padUpper) + base32StdUpperPad.EncodeToString(data), nil
-	case Base32hexPad:
-		return string(Base32hexPad) + base32HexLowerPad.EncodeToString(data), nil
-	case Base32hexPadUpper:
-		return string(Base32hexPadUpper) + base32HexUpperPad.EncodeToString(data), nil
-	case Base36:
-		return string(Base36) + b36.EncodeToStringLc(data), nil
-	case Base36Upper:
-		return string(Base36Upper) + b36.EncodeToStringUc(data), nil
-	case Base58BTC:
-		return string(Base58BTC) + b58.EncodeAlphabet(data, b58.BTCAlphabet), nil
-	case Base58Flickr:
-		return string(Base58Flickr) + b58.EncodeAlphabet(data, b58.FlickrAlphabet), nil
-	case Base64pad:
-		return string(Base64pad) + base64.StdEncoding.EncodeToString(data), nil
-	case Base64urlPad:
-		return string(Base64urlPad) + base64.URLEncoding.EncodeToString(data), nil
-	case Base64url:
-		return string(Base64url) + base64.RawURLEncoding.EncodeToString(data), nil
-	case Base64:
-		return string(Base64) + base64.RawStdEncoding.EncodeToString(data), nil
-	case Base256Emoji:
-		return string(Base256Emoji) + base256emojiEncode(data), nil
-	default:
+	c, ok := lookupCodec(base)
+	if !ok {
 		return "", ErrUnsupportedEncoding
 	}
+	out := make([]byte, 0, utf8.RuneLen(rune(base))+c.EncodedLen(len(data)))
+	out = c.AppendEncode(utf8.AppendRune(out, rune(base)), data)
+	return encodedString(c, out), nil
+}
+
+// AppendEncode appends the multibase string (<encoding><base-encoded-string>)
+// of src with the selected encoding to dst and returns the extended buffer.
+// It doesn't allocate when dst has enough capacity, except for the base10,
+// base36 and base58 encodings of inputs over 128 bytes. It will return an
+// error if the selected base is not known.
+func AppendEncode(dst []byte, base Encoding, src []byte) ([]byte, error) {
+	c, ok := lookupCodec(base)
+	if !ok {
+		return dst, ErrUnsupportedEncoding
+	}
+	dst = utf8.AppendRune(dst, rune(base))
+	return c.AppendEncode(dst, src), nil
 }
 
 // Decode takes a multibase string and decodes into a bytes buffer.
-// It will return an error if the selected base is not known.
+// It will return an error if the selected base is not known, or if the
+// limits of DefaultDecodeOptions are exceeded.
 func Decode(data string) (Encoding, []byte, error) {
-	if len(data) == 0 {
-		return 0, nil, fmt.Errorf("cannot decode multibase for zero length string")
+	enc, out, err := AppendDecode(nil, data)
+	if err != nil {
+		return enc, nil, err
+	}
+	return enc, out, nil
+}
+
+// AppendDecode decodes the multibase string src, appends the decoded data
+// to dst and returns the encoding along with the extended buffer. It
+// doesn't allocate when dst has enough capacity, except for base10, base36
+// and base58 outputs over 128 bytes. On error, dst is returned unchanged.
+func AppendDecode(dst []byte, src string) (Encoding, []byte, error) {
+	return DefaultDecodeOptions.appendDecode(dst, stringBytes(src), nil)
+}
+
+// DecodeBytes is like Decode but takes the multibase string as a byte
+// slice, which saves a copy when it comes from a network buffer or a
+// JSON token.
+func DecodeBytes(src []byte) (Encoding, []byte, error) {
+	enc, out, err := DefaultDecodeOptions.appendDecode(nil, src, nil)
+	if err != nil {
+		return enc, nil, err
+	}
+	return enc, out, nil
+}
+
+// DecodeInto decodes the multibase string src into dst and returns the
+// encoding along with the number of bytes written. It returns
+// io.ErrShortBuffer if the decoded data doesn't fit in dst, the maximum
+// returned by DecodedLen is always enough. On error, the content of dst
+// is unspecified.
+func DecodeInto(dst, src []byte) (Encoding, int, error) {
+	return decodeInto(dst, src, nil)
+}
+
+// decodeInto is DecodeInto for the encodings of allowed, or any encoding
+// if it is nil
+func decodeInto(dst, src []byte, allowed []Encoding) (Encoding, int, error) {
+	o := DefaultDecodeOptions
+	enc, c, data, err := o.parse(src, allowed)
+	if err != nil {
+		return enc, 0, err
+	}
+	if min, _ := c.DecodedLen(data); min > len(dst) {
+		return enc, 0, io.ErrShortBuffer
+	}
+	// Limit the capacity so that the codec never writes past len(dst),
+	// it reallocates instead when the data doesn't fit
+	out, err := c.AppendDecode(dst[:0:len(dst)], data)
+	if err != nil {
+		return enc, 0, payloadError(enc, len(src)-len(data), data, err)
+	}
+	if len(out) > len(dst) {
+		return enc, 0, io.ErrShortBuffer
+	}
+	// the codecs may also grow the buffer on their own estimate, copy
+	// the result back if that happened
+	if len(out) > 0 && unsafe.SliceData(out) != unsafe.SliceData(dst) {
+		copy(dst, out)
+	}
+	if err := o.checkOutput(enc, len(out)); err != nil {
+		return enc, 0, err
+	}
+	if o.Strict {
+		if err := checkCanonical(enc, len(src)-len(data), data, c.AppendEncode(nil, dst[:len(out)])); err != nil {
+			return enc, 0, err
+		}
 	}
+	return enc, len(out), nil
+}
+
+// EncodedLen returns the length in bytes of the multibase string of n
+// bytes encoded with base, prefix included (1 byte, or 4 for
+// Base256Emoji). It is the maximum length for the encodings where it
+// depends on the data: base10, base36 and base58 reach it when the data
+// doesn't start with a zero byte, Base256Emoji when every emoji is 4
+// bytes long.
+func EncodedLen(base Encoding, n int) (int, error) {
+	c, ok := lookupCodec(base)
+	if !ok {
+		return 0, ErrUnsupportedEncoding
+	}
+	return utf8.RuneLen(rune(base)) + c.EncodedLen(n), nil
+}
+
+// DecodedLen returns the bounds of the number of bytes the multibase
+// string s decodes to, without decoding it. They are equal except for
+// the base10, base36 and base58 encodings where they are as tight as the
+// number of digits allows. The result is only meaningful if s is valid.
+func DecodedLen(s string) (min, max int, err error) {
+	_, c, data, err := parsePrefix(stringBytes(s))
+	if err != nil {
+		return 0, 0, err
+	}
+	min, max = c.DecodedLen(data)
+	return min, max, nil
+}
 
-	r, _ := utf8.DecodeRuneInString(data)
+// parsePrefix reads the multibase prefix of src, it returns the
+// encoding, its codec and the data following the prefix
+func parsePrefix(src []byte) (Encoding, Codec, []byte, error) {
+	if len(src) == 0 {
+		return 0, nil, nil, prefixError(src)
+	}
+
+	r, n := utf8.DecodeRune(src)
 	enc := Encoding(r)
+	c, ok := lookupCodec(enc)
+	if !ok {
+		return -1, nil, nil, prefixError(src)
+	}
+	return enc, c, src[n:], nil
+}
 
-	switch enc {
-	case Identity:
-		return Identity, []byte(data[1:]), nil
-	case Base2:
-		bytes, err := decodeBinaryString(data[1:])
-		return enc, bytes, err
-	case Base16, Base16Upper:
-		bytes, err := hex.DecodeString(data[1:])
-		return enc, bytes, err
-	case Base32, Base32Upper:
-		bytes, err := b32.RawStdEncoding.DecodeString(data[1:])
-		return enc, bytes, err
-	case Base32hex, Base32hexUpper:
-		bytes, err := b32.RawHexEncoding.DecodeString(data[1:])
-		return enc, bytes, err
-	case Base32pad, Base32padUpper:
-		bytes, err := b32.StdEncoding.DecodeString(data[1:])
-		return enc, bytes, err
-	case Base32hexPad, Base32hexPadUpper:
-		bytes, err := b32.HexEncoding.DecodeString(data[1:])
-		return enc, bytes, err
-	case Base36, Base36Upper:
-		bytes, err := b36.DecodeString(data[1:])
-		return enc, bytes, err
-	case Base58BTC:
-		bytes, err := b58.DecodeAlphabet(data[1:], b58.BTCAlphabet)
-		return Base58BTC, bytes, err
-	case Base58Flickr:
-		bytes, err := b58.DecodeAlphabet(data[1:], b58.FlickrAlphabet)
-		return Base58Flickr, bytes, err
-	case Base64pad:
-		bytes, err := base64.StdEncoding.DecodeString(data[1:])
-		return Base64pad, bytes, err
-	case Base64urlPad:
-		bytes, err := base64.URLEncoding.DecodeString(data[1:])
-		return Base64urlPad, bytes, err
-	case Base64:
-		bytes, err := base64.RawStdEncoding.DecodeString(data[1:])
-		return Base64, bytes, err
-	case Base64url:
-		bytes, err := base64.RawURLEncoding.DecodeString(data[1:])
-		return Base64url, bytes, err
-	case Base256Emoji:
-		bytes, err := base256emojiDecode(data[4:])
-		return Base256Emoji, bytes, err
-	default:
-		return -1, nil, ErrUnsupportedEncoding
+// encodedString returns the output of c as a string, without copying it
+// for the builtin codecs which don't keep any reference to it
+func encodedString(c Codec, out []byte) string {
+	if _, ok := c.(registeredCodec); ok {
+		return string(out)
 	}
+	return unsafe.String(unsafe.SliceData(out), len(out))
+}
+
+// stringBytes returns the bytes of s without copying them, the builtin
+// codecs never write to their input and registeredCodec copies it
+func stringBytes(s string) []byte {
+	return unsafe.Slice(unsafe.StringData(s), len(s))
 }
@@ -3,6 +3,8 @@ package multibase
 import (
 	"bytes"
 	"crypto/rand"
+	"errors"
+	"io"
 	"sort"
 	"testing"
 )
@@ -26,6 +28,8 @@ var sampleBytes = []byte("Decentralize everything!!!")
 var encodedSamples = map[Encoding]string{
 	Identity:          string(rune(0x00)) + "Decentralize everything!!!",
 	Base2:             "00100010001100101011000110110010101101110011101000111001001100001011011000110100101111010011001010010000001100101011101100110010101110010011110010111010001101000011010010110111001100111001000010010000100100001",
+	Base8:             "72106254331267164344605543227514510062566312711713506415133463441102204",
+	Base10:            "9109908211473026300072608683330054595334719246534349983154512161",
 	Base16:            "f446563656e7472616c697a652065766572797468696e67212121",
 	Base16Upper:       "F446563656E7472616C697A652065766572797468696E67212121",
 	Base32:            "birswgzloorzgc3djpjssazlwmvzhs5dinfxgoijbee",
@@ -36,14 +40,17 @@ var encodedSamples = map[Encoding]string{
 	Base32hexUpper:    "V8HIM6PBEEHP62R39F9II0PBMCLP7IT38D5N6E89144",
 	Base32hexPad:      "t8him6pbeehp62r39f9ii0pbmclp7it38d5n6e89144======",
 	Base32hexPadUpper: "T8HIM6PBEEHP62R39F9II0PBMCLP7IT38D5N6E89144======",
+	Base32z:           "het1sg3mqqt3gn5djxj11y3msci3817depfzgqejbrr",
 	Base36:            "km552ng4dabi4neu1oo8l4i5mndwmpc3mkukwtxy9",
 	Base36Upper:       "KM552NG4DABI4NEU1OO8L4I5MNDWMPC3MKUKWTXY9",
+	Base45:            "R4T8KPCG/DVKEXVDDLFD44O/EALEAWEZEDV1DL84",
 	Base58BTC:         "z36UQrhJq9fNDS7DiAHM9YXqDHMPfr4EMArvt",
 	Base58Flickr:      "Z36tpRGiQ9Endr7dHahm9xwQdhmoER4emaRVT",
 	Base64:            "mRGVjZW50cmFsaXplIGV2ZXJ5dGhpbmchISE",
 	Base64url:         "uRGVjZW50cmFsaXplIGV2ZXJ5dGhpbmchISE",
 	Base64pad:         "MRGVjZW50cmFsaXplIGV2ZXJ5dGhpbmchISE=",
 	Base64urlPad:      "URGVjZW50cmFsaXplIGV2ZXJ5dGhpbmchISE=",
+	Proquint:          "pro-hidoj-katoj-kunuh-lanod-kudon-lonoj-fadoj-linoj-lanun-lidom-kojov-kisod-fahod",
 	Base256Emoji:      "🚀💛✋💃✋😻😈🥺🤤🍀🌟💐✋😅✋💦✋🥺🏃😈😴🌟😻😝👏👏👏",
 }
 
@@ -218,6 +225,237 @@ func TestRoundTrip(t *testing.T) {
 	}
 }
 
+func TestBase32z(t *testing.T) {
+	testEncode(t, Base32z, []byte("yes mani !"), "hxf1zgedpcfzg1ebb")
+	testDecode(t, Base32z, []byte("yes mani !"), "hxf1zgedpcfzg1ebb")
+	testEncode(t, Base32z, []byte{0x00, 0xff}, "hyd9o")
+	testDecode(t, Base32z, []byte{0x00, 0xff}, "hyd9o")
+
+	// 'v' and '2' are not part of the z-base-32 alphabet
+	for _, s := range []string{"hv", "h2yyy"} {
+		if _, _, err := Decode(s); err == nil {
+			t.Errorf("decoding %q should fail", s)
+		}
+	}
+}
+
+func TestAppend(t *testing.T) {
+	for encoding := range EncodingToStr {
+		dst := []byte("prefix")
+		out, err := AppendEncode(dst, encoding, sampleBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != "prefix"+encodedSamples[encoding] {
+			t.Errorf("AppendEncode failed for %s, got %q", EncodingToStr[encoding], out)
+		}
+
+		e, out, err := AppendDecode(dst, encodedSamples[encoding])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e != encoding {
+			t.Errorf("wrong encoding code, expected: %c (%d), got %c (%d)", encoding, encoding, e, e)
+		}
+		if string(out) != "prefix"+string(sampleBytes) {
+			t.Errorf("AppendDecode failed for %s, got %q", EncodingToStr[encoding], out)
+		}
+	}
+
+	dst := []byte("prefix")
+	if _, out, err := AppendDecode(dst, "f0g"); err == nil || string(out) != "prefix" {
+		t.Errorf("AppendDecode should fail and leave dst unchanged, got %q, %v", out, err)
+	}
+	if out, err := AppendEncode(dst, 'q', sampleBytes); err != ErrUnsupportedEncoding || string(out) != "prefix" {
+		t.Errorf("AppendEncode should fail and leave dst unchanged, got %q, %v", out, err)
+	}
+}
+
+func TestAppendAllocs(t *testing.T) {
+	dst := make([]byte, 0, 1024)
+	for encoding := range EncodingToStr {
+		encoded, err := Encode(encoding, benchmarkBuf[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		allocs := testing.AllocsPerRun(10, func() {
+			AppendEncode(dst[:0], encoding, benchmarkBuf[:])
+		})
+		if allocs != 0 {
+			t.Errorf("AppendEncode with %s allocated %v times", EncodingToStr[encoding], allocs)
+		}
+		allocs = testing.AllocsPerRun(10, func() {
+			AppendDecode(dst[:0], encoded)
+		})
+		if allocs != 0 {
+			t.Errorf("AppendDecode with %s allocated %v times", EncodingToStr[encoding], allocs)
+		}
+	}
+}
+
+func TestDecodeBytes(t *testing.T) {
+	for encoding := range EncodingToStr {
+		e, out, err := DecodeBytes([]byte(encodedSamples[encoding]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e != encoding {
+			t.Errorf("wrong encoding code, expected: %c (%d), got %c (%d)", encoding, encoding, e, e)
+		}
+		if !bytes.Equal(out, sampleBytes) {
+			t.Errorf("DecodeBytes failed for %s, got %q", EncodingToStr[encoding], out)
+		}
+	}
+
+	if _, _, err := DecodeBytes(nil); err == nil {
+		t.Error("DecodeBytes should fail with an empty input")
+	}
+	if _, _, err := DecodeBytes([]byte("q1")); !errors.Is(err, ErrUnsupportedEncoding) {
+		t.Errorf("DecodeBytes should fail with an unsupported encoding, got %v", err)
+	}
+	if _, out, err := DecodeBytes([]byte("f0g")); err == nil || out != nil {
+		t.Errorf("DecodeBytes should fail with invalid data, got %q, %v", out, err)
+	}
+}
+
+func TestDecodeInto(t *testing.T) {
+	for encoding := range EncodingToStr {
+		src := []byte(encodedSamples[encoding])
+		_, max, err := DecodedLen(encodedSamples[encoding])
+		if err != nil {
+			t.Fatal(err)
+		}
+		dst := make([]byte, max)
+		e, n, err := DecodeInto(dst, src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e != encoding {
+			t.Errorf("wrong encoding code, expected: %c (%d), got %c (%d)", encoding, encoding, e, e)
+		}
+		if !bytes.Equal(dst[:n], sampleBytes) {
+			t.Errorf("DecodeInto failed for %s, got %q", EncodingToStr[encoding], dst[:n])
+		}
+
+		// exactly enough room
+		dst = make([]byte, len(sampleBytes))
+		if _, n, err := DecodeInto(dst, src); err != nil || !bytes.Equal(dst[:n], sampleBytes) {
+			t.Errorf("DecodeInto failed for %s with an exact buffer, got %q, %v", EncodingToStr[encoding], dst[:n], err)
+		}
+
+		if _, _, err := DecodeInto(dst[:len(dst)-1], src); err != io.ErrShortBuffer {
+			t.Errorf("DecodeInto should fail with a short buffer for %s, got %v", EncodingToStr[encoding], err)
+		}
+
+		allocs := testing.AllocsPerRun(10, func() {
+			DecodeInto(dst, src)
+		})
+		if allocs != 0 {
+			t.Errorf("DecodeInto with %s allocated %v times", EncodingToStr[encoding], allocs)
+		}
+	}
+}
+
+// TestDecodeIntoBigNumber checks that DecodeInto doesn't write past dst
+// when the data is longer than the minimum given by DecodedLen
+func TestDecodeIntoBigNumber(t *testing.T) {
+	data := []byte{0x19, 0x53, 0x45, 0x6e, 0xd4, 0xf7, 0x84}
+	for _, base := range []Encoding{Base58BTC, Base58Flickr} {
+		src, err := Encode(base, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if min, _, _ := DecodedLen(src); min >= len(data) {
+			t.Fatalf("%s: expected a minimum below %d, got %d", EncodingToStr[base], len(data), min)
+		}
+
+		buf := []byte("XXXXXXXXXXXX")
+		for _, l := range []int{len(data) - 1, len(data)} {
+			_, n, err := DecodeInto(buf[:l], []byte(src))
+			if l < len(data) && err != io.ErrShortBuffer {
+				t.Errorf("%s: expected io.ErrShortBuffer with %d bytes, got %d, %v", EncodingToStr[base], l, n, err)
+			}
+			if l == len(data) && (err != nil || !bytes.Equal(buf[:n], data)) {
+				t.Errorf("%s: DecodeInto failed with %d bytes, got %q, %v", EncodingToStr[base], l, buf[:n], err)
+			}
+			if string(buf[l:]) != "XXXXXXXXXXXX"[l:] {
+				t.Errorf("%s: DecodeInto wrote past dst: %q", EncodingToStr[base], buf)
+			}
+		}
+	}
+}
+
+// bigNumberEncodings have a length depending on the value of the data,
+// not only on its size
+var bigNumberEncodings = map[Encoding]bool{
+	Base10:       true,
+	Base36:       true,
+	Base36Upper:  true,
+	Base58BTC:    true,
+	Base58Flickr: true,
+}
+
+func TestEncodedLen(t *testing.T) {
+	buf := make([]byte, 64)
+	ones := bytes.Repeat([]byte{0xFF}, 64)
+	for encoding := range EncodingToStr {
+		for n := 0; n <= len(buf); n++ {
+			rand.Read(buf[:n])
+			l, err := EncodedLen(encoding, n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, data := range [][]byte{buf[:n], ones[:n]} {
+				encoded, err := Encode(encoding, data)
+				if err != nil {
+					t.Fatal(err)
+				}
+				exact := !bigNumberEncodings[encoding] && encoding != Base256Emoji
+				if len(encoded) > l || exact && len(encoded) != l {
+					t.Errorf("EncodedLen(%s, %d) = %d, encoded to %d bytes", EncodingToStr[encoding], n, l, len(encoded))
+				}
+			}
+			// the bound is reached by big-number encodings
+			if encoded, _ := Encode(encoding, ones[:n]); encoding != Base256Emoji && len(encoded) != l {
+				t.Errorf("EncodedLen(%s, %d) = %d, not tight for %d bytes", EncodingToStr[encoding], n, l, len(encoded))
+			}
+		}
+	}
+
+	if _, err := EncodedLen('q', 1); !errors.Is(err, ErrUnsupportedEncoding) {
+		t.Errorf("EncodedLen should fail with an unsupported encoding, got %v", err)
+	}
+}
+
+func TestDecodedLen(t *testing.T) {
+	buf := make([]byte, 64)
+	for encoding := range EncodingToStr {
+		for n := 0; n <= len(buf); n++ {
+			rand.Read(buf[:n])
+			for _, data := range [][]byte{buf[:n], bytes.Repeat([]byte{0xFF}, n), make([]byte, n)} {
+				encoded, err := Encode(encoding, data)
+				if err != nil {
+					t.Fatal(err)
+				}
+				min, max, err := DecodedLen(encoded)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if min > n || max < n || !bigNumberEncodings[encoding] && min != max {
+					t.Errorf("DecodedLen(%q) = %d, %d for %d bytes", encoded, min, max, n)
+				}
+			}
+		}
+	}
+
+	if _, _, err := DecodedLen(""); err == nil {
+		t.Error("DecodedLen should fail with an empty string")
+	}
+	if _, _, err := DecodedLen("q1"); !errors.Is(err, ErrUnsupportedEncoding) {
+		t.Errorf("DecodedLen should fail with an unsupported encoding, got %v", err)
+	}
+}
+
 var benchmarkBuf [36]byte // typical CID size
 var benchmarkCodecs []string
 
@@ -297,3 +535,39 @@ func BenchmarkDecode(b *testing.B) {
 		})
 	}
 }
+
+func BenchmarkAppendEncode(b *testing.B) {
+	b.ResetTimer()
+
+	for _, name := range benchmarkCodecs {
+		b.Run(name, func(b *testing.B) {
+			base := Encodings[name]
+			dst := make([]byte, 0, 1024)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, err := AppendEncode(dst[:0], base, benchmarkBuf[:])
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkAppendDecode(b *testing.B) {
+	b.ResetTimer()
+
+	for _, name := range benchmarkCodecs {
+		b.Run(name, func(b *testing.B) {
+			enc, _ := Encode(Encodings[name], benchmarkBuf[:])
+			dst := make([]byte, 0, 1024)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, _, err := AppendDecode(dst[:0], enc)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
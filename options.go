@@ -0,0 +1,197 @@
+package multibase
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Kinds of DecodeError returned by strict decoding when a multibase
+// string decodes fine but isn't the canonical encoding of its data.
+var (
+	// ErrWrongCase is returned when the data doesn't use the case of
+	// the declared prefix, for instance uppercase data after 'b'.
+	ErrWrongCase = fmt.Errorf("data case does not match the encoding")
+	// ErrNonCanonicalPadding is returned when padding is missing or
+	// superfluous.
+	ErrNonCanonicalPadding = fmt.Errorf("non-canonical padding")
+	// ErrNonZeroTrailingBits is returned when the bits of the last
+	// character that don't make a whole byte are not zero.
+	ErrNonZeroTrailingBits = fmt.Errorf("non-zero trailing bits")
+	// ErrLeadingZeros is returned when the data is missing leading
+	// zero digits, like a base2 string that isn't a multiple of 8 digits.
+	ErrLeadingZeros = fmt.Errorf("ambiguous leading zeros")
+	// ErrNonCanonical is returned for any other difference with the
+	// canonical encoding, such as line breaks or a proquint without its
+	// "ro-" prefix.
+	ErrNonCanonical = fmt.Errorf("non-canonical encoding")
+)
+
+// DefaultBigNumberMaxInputLen is the limit of the length of the base10,
+// base36 and base58 strings in DefaultDecodeOptions. Decoding them takes
+// a lot more time than the other encodings, about 40ms at this limit.
+const DefaultBigNumberMaxInputLen = 256 << 10
+
+// DecodeOptions configures the decoding of multibase strings.
+type DecodeOptions struct {
+	// Strict only accepts the canonical encoding of the data, the one
+	// Encode returns, so that every byte sequence has exactly one
+	// valid multibase string per encoding.
+	Strict bool
+	// MaxInputLen is the maximum length in bytes of the multibase
+	// strings, prefix included, zero or a negative value means no limit.
+	MaxInputLen int
+	// MaxBigNumberInputLen is a lower MaxInputLen for the big-number
+	// encodings (base10, base36, base58), which take more time to decode
+	// than the other encodings. Zero or a negative value means that
+	// MaxInputLen applies.
+	MaxBigNumberInputLen int
+	// MaxOutputLen is the maximum length in bytes of the decoded data,
+	// zero or a negative value means no limit.
+	MaxOutputLen int
+}
+
+// DefaultDecodeOptions are the options of Decode, AppendDecode,
+// DecodeBytes, DecodeInto, DecodeRaw and Decoder. They only limit the
+// big-number encodings to DefaultBigNumberMaxInputLen, servers can set
+// them once at startup to further limit the resources spent on untrusted
+// input. They must not be modified concurrently with decoding.
+var DefaultDecodeOptions = DecodeOptions{MaxBigNumberInputLen: DefaultBigNumberMaxInputLen}
+
+// DecodeStrict is like Decode but rejects any multibase string that is
+// not the canonical encoding of its data.
+func DecodeStrict(data string) (Encoding, []byte, error) {
+	o := DefaultDecodeOptions
+	o.Strict = true
+	return o.Decode(data)
+}
+
+// Decode takes a multibase string and decodes it according to the
+// options.
+func (o DecodeOptions) Decode(data string) (Encoding, []byte, error) {
+	enc, out, err := o.appendDecode(nil, stringBytes(data), nil)
+	if err != nil {
+		return enc, nil, err
+	}
+	return enc, out, nil
+}
+
+// appendDecode decodes src and appends the result to dst, the encoding
+// must be part of allowed unless it is nil. On error, dst is returned
+// unchanged.
+func (o DecodeOptions) appendDecode(dst, src []byte, allowed []Encoding) (Encoding, []byte, error) {
+	enc, c, data, err := o.parse(src, allowed)
+	if err != nil {
+		return enc, dst, err
+	}
+	out, err := o.appendDecodePayload(dst, enc, c, len(src)-len(data), data)
+	return enc, out, err
+}
+
+// appendDecodePayload decodes data, the multibase string without its
+// prefix of n bytes, and appends the result to dst. On error, dst is
+// returned unchanged.
+func (o DecodeOptions) appendDecodePayload(dst []byte, enc Encoding, c Codec, n int, data []byte) ([]byte, error) {
+	out, err := c.AppendDecode(dst, data)
+	if err != nil {
+		return dst, payloadError(enc, n, data, err)
+	}
+	if err := o.checkOutput(enc, len(out)-len(dst)); err != nil {
+		return dst, err
+	}
+	if o.Strict {
+		decoded := out[len(dst):]
+		if err := checkCanonical(enc, n, data, c.AppendEncode(nil, decoded)); err != nil {
+			return dst, err
+		}
+	}
+	return out, nil
+}
+
+// parse reads the prefix of src like parsePrefix, and checks that the
+// encoding is allowed and the limits are respected before any decoding
+func (o DecodeOptions) parse(src []byte, allowed []Encoding) (Encoding, Codec, []byte, error) {
+	enc, c, data, err := parsePrefix(src)
+	if err != nil {
+		return enc, nil, nil, err
+	}
+	if allowed != nil {
+		if err := allowedError(enc, allowed); err != nil {
+			return enc, nil, nil, err
+		}
+	}
+	if err := o.checkInput(enc, c, src, data); err != nil {
+		return enc, nil, nil, err
+	}
+	return enc, c, data, nil
+}
+
+// checkInput checks the limits on src, the multibase string, and data,
+// the part following its prefix
+func (o DecodeOptions) checkInput(enc Encoding, c Codec, src, data []byte) error {
+	maxIn := o.MaxInputLen
+	if _, ok := c.(*bigBase); ok && o.MaxBigNumberInputLen > 0 && (maxIn <= 0 || o.MaxBigNumberInputLen < maxIn) {
+		maxIn = o.MaxBigNumberInputLen
+	}
+	if maxIn > 0 && len(src) > maxIn {
+		return &DecodeError{Encoding: enc, Offset: maxIn, Rune: -1, Kind: ErrInputTooLarge,
+			Err: fmt.Errorf("string of %d bytes, over the limit of %d", len(src), maxIn)}
+	}
+	if o.MaxOutputLen > 0 {
+		if min, _ := c.DecodedLen(data); min > o.MaxOutputLen {
+			return o.outputError(enc, min)
+		}
+	}
+	return nil
+}
+
+// checkOutput checks the length n of the decoded data when it could not
+// be known beforehand
+func (o DecodeOptions) checkOutput(enc Encoding, n int) error {
+	if o.MaxOutputLen > 0 && n > o.MaxOutputLen {
+		return o.outputError(enc, n)
+	}
+	return nil
+}
+
+func (o DecodeOptions) outputError(enc Encoding, n int) error {
+	return &DecodeError{Encoding: enc, Rune: -1, Kind: ErrInputTooLarge,
+		Err: fmt.Errorf("decodes to %d bytes, over the limit of %d", n, o.MaxOutputLen)}
+}
+
+// checkCanonical compares src, the data following a prefix of n bytes,
+// with canon, the encoding of its decoded data, and reports the first
+// violation found
+func checkCanonical(enc Encoding, n int, src, canon []byte) error {
+	if bytes.Equal(src, canon) {
+		return nil
+	}
+
+	i := 0
+	for i < len(src) && i < len(canon) && src[i] == canon[i] {
+		i++
+	}
+	violation := ErrNonCanonical
+	srcData, canonData := bytes.TrimRight(src, "="), bytes.TrimRight(canon, "=")
+	switch {
+	// src is canon without some of its leading zero digits
+	case len(canon) > len(src) && bytes.HasSuffix(canon, src) &&
+		len(bytes.Trim(canon[:len(canon)-len(src)], string(canon[:1]))) == 0:
+		i = 0
+		violation = ErrLeadingZeros
+	case bytes.EqualFold(src, canon):
+		violation = ErrWrongCase
+	case bytes.EqualFold(srcData, canonData):
+		violation = ErrNonCanonicalPadding
+	// only the last character differs, by bits that don't make a byte
+	case len(srcData) == len(canonData) && len(srcData) > 0 &&
+		bytes.EqualFold(srcData[:len(srcData)-1], canonData[:len(canonData)-1]):
+		i = len(srcData) - 1
+		violation = ErrNonZeroTrailingBits
+	}
+	e := &DecodeError{Encoding: enc, Offset: n + i, Rune: -1, Kind: violation}
+	if i < len(src) {
+		e.Rune, _ = utf8.DecodeRune(src[i:])
+	}
+	return e
+}
@@ -0,0 +1,159 @@
+package multibase
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecodeStrict(t *testing.T) {
+	for encoding, sample := range encodedSamples {
+		e, out, err := DecodeStrict(sample)
+		if err != nil {
+			t.Fatalf("DecodeStrict failed for %s: %v", EncodingToStr[encoding], err)
+		}
+		if e != encoding || !bytes.Equal(out, sampleBytes) {
+			t.Errorf("DecodeStrict failed for %s, got %c %q", EncodingToStr[encoding], e, out)
+		}
+	}
+}
+
+func TestDecodeStrictViolations(t *testing.T) {
+	for _, tc := range []struct {
+		data string
+		err  error
+	}{
+		{"b", nil},
+		{"bmzxw6", nil},
+		{"bMZXW6", ErrWrongCase},
+		{"BMZXW6", nil},
+		{"Bmzxw6", ErrWrongCase},
+		{"bmzxw7", ErrNonZeroTrailingBits},
+		{"BMzXW7", ErrNonZeroTrailingBits},
+		{"cmzxw6===", nil},
+		{"cMZXW6===", ErrWrongCase},
+		{"bmzx\nw6", ErrNonCanonical},
+		{"fdead", nil},
+		{"fDEAD", ErrWrongCase},
+		{"Fdead", ErrWrongCase},
+		{"k2lsz", nil},
+		{"k2LSZ", ErrWrongCase},
+		{"mZm8", nil},
+		{"mZm9", ErrNonZeroTrailingBits},
+		{"MZm8=", nil},
+		{"MZm9=", ErrNonZeroTrailingBits},
+		{"MZm8=\n", ErrNonCanonical},
+		{"7000", nil},
+		{"7001", ErrNonZeroTrailingBits},
+		{"000000101", nil},
+		{"00101", ErrLeadingZeros},
+		{"900", nil},
+		{"z11", nil},
+		{"pro-hidoj", nil},
+		{"phidoj", ErrNonCanonical},
+		{"pro-hab", nil},
+		{"pro-haf", ErrNonZeroTrailingBits},
+	} {
+		_, _, err := DecodeStrict(tc.data)
+		if !errors.Is(err, tc.err) {
+			t.Errorf("DecodeStrict(%q) = %v, expected %v", tc.data, err, tc.err)
+		}
+		if _, _, err := Decode(tc.data); err != nil {
+			t.Errorf("Decode(%q) failed: %v", tc.data, err)
+		}
+	}
+}
+
+func TestCheckCanonicalPadding(t *testing.T) {
+	for _, src := range []string{"mzxw6", "mzxw6=", "mzxw6====="} {
+		if err := checkCanonical(Base32pad, 1, []byte(src), []byte("mzxw6===")); !errors.Is(err, ErrNonCanonicalPadding) {
+			t.Errorf("checkCanonical(%q) = %v, expected %v", src, err, ErrNonCanonicalPadding)
+		}
+	}
+}
+
+func TestDecodeLimits(t *testing.T) {
+	const limit = 16 << 10
+	big58 := "z" + strings.Repeat("2", limit)
+	big16 := "f" + strings.Repeat("00", limit)
+	default58 := "z" + strings.Repeat("2", DefaultBigNumberMaxInputLen)
+	default16 := "f" + strings.Repeat("00", DefaultBigNumberMaxInputLen)
+	for _, tc := range []struct {
+		opts DecodeOptions
+		data string
+		err  bool
+	}{
+		{DecodeOptions{}, "f68656c6c6f", false},
+		{DecodeOptions{MaxInputLen: 11}, "f68656c6c6f", false},
+		{DecodeOptions{MaxInputLen: 10}, "f68656c6c6f", true},
+		{DecodeOptions{MaxOutputLen: 5}, "f68656c6c6f", false},
+		{DecodeOptions{MaxOutputLen: 4}, "f68656c6c6f", true},
+		{DecodeOptions{MaxOutputLen: 4}, "z1111", false},
+		{DecodeOptions{MaxOutputLen: 4}, "z11111", true},
+		{DecodeOptions{MaxOutputLen: 2}, "zCn8eVZg", true},
+		{DecodeOptions{MaxOutputLen: 2}, "\U0001F680\U0001F4AA✅\U0001F4AA", true},
+		{DecodeOptions{}, big58, false},
+		{DecodeOptions{MaxInputLen: -1}, big58, false},
+		{DefaultDecodeOptions, big58, false},
+		{DefaultDecodeOptions, default58, true},
+		{DefaultDecodeOptions, default58[:DefaultBigNumberMaxInputLen], false},
+		{DefaultDecodeOptions, default16, false},
+		{DecodeOptions{}, default58, false},
+		{DecodeOptions{MaxBigNumberInputLen: limit}, big58, true},
+		{DecodeOptions{MaxBigNumberInputLen: limit}, big58[:limit], false},
+		{DecodeOptions{MaxBigNumberInputLen: limit, MaxInputLen: 100}, big58[:limit], true},
+		{DecodeOptions{MaxBigNumberInputLen: limit, MaxInputLen: 2 * limit}, big58, true},
+		{DecodeOptions{MaxBigNumberInputLen: limit}, big16, false},
+		{DecodeOptions{MaxInputLen: limit}, big16, true},
+	} {
+		_, _, err := tc.opts.Decode(tc.data)
+		if !tc.err {
+			if err != nil {
+				t.Errorf("%+v: unexpected error decoding %.20q: %v", tc.opts, tc.data, err)
+			}
+			continue
+		}
+		var de *DecodeError
+		if !errors.As(err, &de) || !errors.Is(err, ErrInputTooLarge) {
+			t.Errorf("%+v: expected ErrInputTooLarge decoding %.20q, got %v", tc.opts, tc.data, err)
+		}
+	}
+}
+
+func TestDefaultDecodeOptions(t *testing.T) {
+	defer func(o DecodeOptions) { DefaultDecodeOptions = o }(DefaultDecodeOptions)
+	DefaultDecodeOptions = DecodeOptions{MaxInputLen: 8}
+
+	const data = "f68656c6c6f"
+	if _, _, err := Decode(data); !errors.Is(err, ErrInputTooLarge) {
+		t.Errorf("Decode: expected ErrInputTooLarge, got %v", err)
+	}
+	if _, out, err := AppendDecode([]byte("x"), data); !errors.Is(err, ErrInputTooLarge) || string(out) != "x" {
+		t.Errorf("AppendDecode: expected ErrInputTooLarge and dst unchanged, got %q, %v", out, err)
+	}
+	if _, _, err := DecodeBytes([]byte(data)); !errors.Is(err, ErrInputTooLarge) {
+		t.Errorf("DecodeBytes: expected ErrInputTooLarge, got %v", err)
+	}
+	if _, _, err := DecodeInto(make([]byte, 8), []byte(data)); !errors.Is(err, ErrInputTooLarge) {
+		t.Errorf("DecodeInto: expected ErrInputTooLarge, got %v", err)
+	}
+	if _, _, err := MustNewDecoder(Base16).Decode(data); !errors.Is(err, ErrInputTooLarge) {
+		t.Errorf("Decoder: expected ErrInputTooLarge, got %v", err)
+	}
+	if _, out, err := Decode("f6869"); err != nil || string(out) != "hi" {
+		t.Errorf("Decode under the limit: got %q, %v", out, err)
+	}
+
+	if _, _, err := DecodeStrict(data); !errors.Is(err, ErrInputTooLarge) {
+		t.Errorf("DecodeStrict: expected ErrInputTooLarge, got %v", err)
+	}
+
+	DefaultDecodeOptions = DecodeOptions{Strict: true, MaxOutputLen: 2}
+	if _, _, err := DecodeInto(make([]byte, 8), []byte("f646561")); !errors.Is(err, ErrInputTooLarge) {
+		t.Errorf("DecodeInto: expected ErrInputTooLarge, got %v", err)
+	}
+	if _, _, err := DecodeInto(make([]byte, 8), []byte("fDEAD")); !errors.Is(err, ErrWrongCase) {
+		t.Errorf("DecodeInto: expected ErrWrongCase, got %v", err)
+	}
+}
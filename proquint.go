@@ -0,0 +1,158 @@
+package multibase
+
+import (
+	"bytes"
+	"slices"
+)
+
+const (
+	proquintConsonants = "bdfghjklmnprstvz"
+	proquintVowels     = "aiou"
+
+	// proquintPrefix starts every encoded proquint, making the full
+	// multibase string start with "pro-"
+	proquintPrefix = "ro-"
+)
+
+var proquintConsonantMap, proquintVowelMap [256]byte
+
+func init() {
+	for i := range proquintConsonantMap {
+		proquintConsonantMap[i] = 0xFF
+		proquintVowelMap[i] = 0xFF
+	}
+	for i := 0; i < len(proquintConsonants); i++ {
+		proquintConsonantMap[proquintConsonants[i]] = byte(i)
+	}
+	for i := 0; i < len(proquintVowels); i++ {
+		proquintVowelMap[proquintVowels[i]] = byte(i)
+	}
+}
+
+// proquintCodec is the proquint encoding, see
+// https://arxiv.org/html/0901.4016
+type proquintCodec struct{}
+
+// proquintEncodedLen returns the length of the proquint encoding of n
+// bytes: the "ro-" prefix, five characters per 16 bit word, three for
+// a trailing odd byte and a dash between every group
+func proquintEncodedLen(n int) int {
+	l := len(proquintPrefix) + n/2*5 + n%2*3
+	if groups := (n + 1) / 2; groups > 1 {
+		l += groups - 1
+	}
+	return l
+}
+
+func (proquintCodec) EncodedLen(n int) int {
+	return proquintEncodedLen(n)
+}
+
+// DecodedLen counts the groups of src, every one of them decodes to two
+// bytes except a trailing group of three characters
+func (proquintCodec) DecodedLen(src []byte) (min, max int) {
+	l := len(src)
+	if bytes.HasPrefix(src, []byte(proquintPrefix)) {
+		l -= len(proquintPrefix)
+	}
+	n := (l + 1) / 6 * 2
+	if (l+1)%6 == 4 {
+		n++
+	}
+	return n, n
+}
+
+// AppendEncode appends the proquint encoding of src to dst. Every 16
+// bit word becomes a consonant-vowel-consonant-vowel-consonant group, a
+// trailing odd byte becomes a consonant-vowel-consonant group with its
+// last consonant padded with two zero bits.
+func (proquintCodec) AppendEncode(dst, src []byte) []byte {
+	dst = slices.Grow(dst, proquintEncodedLen(len(src)))
+	dst = append(dst, proquintPrefix...)
+	for j := 0; j < len(src); j += 2 {
+		if j > 0 {
+			dst = append(dst, '-')
+		}
+		if j+1 == len(src) {
+			b := src[j]
+			dst = append(dst,
+				proquintConsonants[b>>4],
+				proquintVowels[b>>2&3],
+				proquintConsonants[b&3<<2])
+			continue
+		}
+		w := uint16(src[j])<<8 | uint16(src[j+1])
+		dst = append(dst,
+			proquintConsonants[w>>12],
+			proquintVowels[w>>10&3],
+			proquintConsonants[w>>6&15],
+			proquintVowels[w>>4&3],
+			proquintConsonants[w&15])
+	}
+	return dst
+}
+
+// AppendDecode decodes src, with or without the "ro-" prefix, and
+// appends the result to dst
+func (c proquintCodec) AppendDecode(dst, src []byte) ([]byte, error) {
+	l, _ := c.DecodedLen(src)
+	return c.decode(slices.Grow(dst, l), src, true)
+}
+
+func (c proquintCodec) validate(src []byte) error {
+	_, err := c.decode(nil, src, false)
+	return err
+}
+
+// decode appends the data decoded from src to dst, or only checks src
+// if emit is false
+func (proquintCodec) decode(dst, src []byte, emit bool) ([]byte, error) {
+	off := 0
+	if bytes.HasPrefix(src, []byte(proquintPrefix)) {
+		off = len(proquintPrefix)
+	}
+
+	for off < len(src) {
+		end := bytes.IndexByte(src[off:], '-')
+		last := end < 0
+		if last {
+			end = len(src)
+		} else if end += off; end == len(src)-1 {
+			// trailing dash
+			return dst, corruptInputError{offset: end}
+		}
+
+		g := src[off:end]
+		if len(g) != 5 && (len(g) != 3 || !last) {
+			return dst, corruptInputError{off, errInputLength}
+		}
+
+		// consonants and vowels alternate, starting with a consonant
+		var w uint
+		for i := 0; i < len(g); i++ {
+			var v byte
+			if i%2 == 0 {
+				v = proquintConsonantMap[g[i]]
+				w = w<<4 | uint(v)
+			} else {
+				v = proquintVowelMap[g[i]]
+				w = w<<2 | uint(v)
+			}
+			if v == 0xFF {
+				return dst, corruptInputError{offset: off + i}
+			}
+		}
+
+		switch {
+		case !emit:
+		case len(g) == 5:
+			dst = append(dst, byte(w>>8), byte(w))
+		default:
+			// drop the two padding bits of the last consonant
+			dst = append(dst, byte(w>>2))
+		}
+
+		off = end + 1
+	}
+	return dst, nil
+}
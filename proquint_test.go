@@ -0,0 +1,44 @@
+package multibase
+
+import "testing"
+
+func TestProquintVectors(t *testing.T) {
+	vectors := []struct {
+		data    []byte
+		encoded string
+	}{
+		{[]byte{}, "pro-"},
+		{[]byte{127, 0, 0, 1}, "pro-lusab-babad"},
+		{[]byte{63, 84, 220, 193}, "pro-gutih-tugad"},
+		{[]byte{0x21}, "pro-fah"},
+		{[]byte("Decentralize everything!!"), "pro-hidoj-katoj-kunuh-lanod-kudon-lonoj-fadoj-linoj-lanun-lidom-kojov-kisod-fah"},
+	}
+	for _, v := range vectors {
+		testEncode(t, Proquint, v.data, v.encoded)
+		testDecode(t, Proquint, v.data, v.encoded)
+	}
+
+	// the "ro-" prefix is optional when decoding
+	testDecode(t, Proquint, []byte{127, 0, 0, 1}, "plusab-babad")
+	testDecode(t, Proquint, []byte{0x21}, "pfah")
+}
+
+func TestProquintInvalid(t *testing.T) {
+	for _, s := range []string{
+		"pro-lusab-",       // trailing dash
+		"pro--lusab",       // empty group
+		"pro-fah-lusab",    // short group before the end
+		"pro-lusa",         // truncated group
+		"pro-lusabb",       // overlong group
+		"pro-ulsab",        // vowel where a consonant is expected
+		"pro-lxsab",        // not in the alphabet
+		"pro-LUSAB",        // upper case is not part of the alphabet
+		"pro-lusab babad",  // space separator
+		"pro-lusab-ro-fah", // prefix in the middle
+	} {
+		_, _, err := Decode(s)
+		if err == nil {
+			t.Errorf("decoding %q should fail", s)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package multibase
+
+// EncodeRaw encodes src with the selected encoding like Encode, but
+// without the multibase prefix.
+func EncodeRaw(base Encoding, src []byte) (string, error) {
+	c, ok := lookupCodec(base)
+	if !ok {
+		return "", ErrUnsupportedEncoding
+	}
+	out := c.AppendEncode(make([]byte, 0, c.EncodedLen(len(src))), src)
+	return encodedString(c, out), nil
+}
+
+// DecodeRaw decodes s, encoded with the selected encoding and without
+// the multibase prefix, within the limits of DefaultDecodeOptions. The
+// offsets of the returned *DecodeError are relative to s.
+func DecodeRaw(base Encoding, s string) ([]byte, error) {
+	c, ok := lookupCodec(base)
+	if !ok {
+		return nil, ErrUnsupportedEncoding
+	}
+	src := stringBytes(s)
+	o := DefaultDecodeOptions
+	if err := o.checkInput(base, c, src, src); err != nil {
+		return nil, err
+	}
+	return o.appendDecodePayload(nil, base, c, 0, src)
+}
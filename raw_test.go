@@ -0,0 +1,57 @@
+package multibase
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRaw(t *testing.T) {
+	for encoding, sample := range encodedSamples {
+		_, n := utf8.DecodeRuneInString(sample)
+		raw, err := EncodeRaw(encoding, sampleBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if raw != sample[n:] {
+			t.Errorf("EncodeRaw failed for %s, expected %q, got %q", encoding, sample[n:], raw)
+		}
+		out, err := DecodeRaw(encoding, raw)
+		if err != nil {
+			t.Fatalf("DecodeRaw failed for %s: %v", encoding, err)
+		}
+		if !bytes.Equal(out, sampleBytes) {
+			t.Errorf("DecodeRaw failed for %s, got %q", encoding, out)
+		}
+	}
+}
+
+func TestRawErrors(t *testing.T) {
+	if _, err := EncodeRaw(Encoding('q'), nil); !errors.Is(err, ErrUnsupportedEncoding) {
+		t.Errorf("EncodeRaw should fail with an unsupported encoding, got %v", err)
+	}
+	if _, err := DecodeRaw(Encoding('q'), ""); !errors.Is(err, ErrUnsupportedEncoding) {
+		t.Errorf("DecodeRaw should fail with an unsupported encoding, got %v", err)
+	}
+
+	// offsets are relative to the raw string
+	_, err := DecodeRaw(Base16, "g0")
+	var de *DecodeError
+	if !errors.As(err, &de) || de.Offset != 0 || de.Rune != 'g' {
+		t.Errorf("expected a corrupt input at 0, got %v", err)
+	}
+	_, err = DecodeRaw(Base58BTC, "Cn8e0")
+	if !errors.As(err, &de) || de.Offset != 4 || de.Rune != '0' || de.Encoding != Base58BTC {
+		t.Errorf("expected a corrupt input at 4, got %v", err)
+	}
+
+	defer func(o DecodeOptions) { DefaultDecodeOptions = o }(DefaultDecodeOptions)
+	DefaultDecodeOptions = DecodeOptions{MaxInputLen: 4}
+	if _, err := DecodeRaw(Base16, "6869"); err != nil {
+		t.Errorf("DecodeRaw under the limit: %v", err)
+	}
+	if _, err := DecodeRaw(Base16, "686969"); !errors.Is(err, ErrInputTooLarge) {
+		t.Errorf("expected ErrInputTooLarge, got %v", err)
+	}
+}
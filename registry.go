@@ -0,0 +1,148 @@
+package multibase
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// registry holds the registered encodings. It is never modified once
+// published, register replaces it with an updated copy so that lookups
+// don't need any locking.
+type registry struct {
+	codecs map[Encoding]Codec
+	infos  map[Encoding]EncodingInfo
+	byName map[string]Encoding
+	all    []EncodingInfo // sorted by prefix
+}
+
+var (
+	registryMu sync.Mutex // serializes register
+	registered atomic.Pointer[registry]
+)
+
+func init() {
+	for _, b := range builtins {
+		b.info.setSafety()
+		if err := register(b.info, b.codec); err != nil {
+			panic(err)
+		}
+		EncodingToStr[b.info.Encoding] = b.info.Name
+		Encodings[b.info.Name] = b.info.Encoding
+	}
+}
+
+// Register adds an encoding with the given prefix and name, making it
+// available to Encode, Decode, NewEncoder, EncoderByName and the other
+// functions of the package. The prefixes assigned or reserved by the
+// multibase specification can't be registered, nor can a prefix or a
+// name already in use. Register is safe for concurrent use, it doesn't
+// add the encoding to EncodingToStr and Encodings.
+func Register(code Encoding, name string, c Codec) error {
+	if _, ok := reservedInfos[code]; ok || isBuiltin(code) {
+		return fmt.Errorf("multibase prefix %q is assigned by the specification", rune(code))
+	}
+	if c == nil {
+		return fmt.Errorf("nil codec for multibase prefix %q", rune(code))
+	}
+	return register(EncodingInfo{Encoding: code, Name: name}, registeredCodec{c})
+}
+
+// registeredCodec wraps the codecs added with Register. Unlike the
+// builtin codecs, they might keep or modify their buffers, so they get
+// a copy of the input of the decoding functions, which may be the bytes
+// of an immutable string, and their encoded output is copied to a new
+// string.
+type registeredCodec struct {
+	Codec
+}
+
+func (c registeredCodec) AppendDecode(dst, src []byte) ([]byte, error) {
+	return c.Codec.AppendDecode(dst, bytes.Clone(src))
+}
+
+func (c registeredCodec) DecodedLen(src []byte) (min, max int) {
+	return c.Codec.DecodedLen(bytes.Clone(src))
+}
+
+func isBuiltin(code Encoding) bool {
+	for _, b := range builtins {
+		if b.info.Encoding == code {
+			return true
+		}
+	}
+	return false
+}
+
+func register(info EncodingInfo, c Codec) error {
+	code, name := info.Encoding, info.Name
+	if c == nil {
+		return fmt.Errorf("nil codec for multibase prefix %q", rune(code))
+	}
+	if !utf8.ValidRune(rune(code)) {
+		return fmt.Errorf("invalid multibase prefix %d", code)
+	}
+	// EncoderByName takes a single character as a prefix
+	if utf8.RuneCountInString(name) < 2 {
+		return fmt.Errorf("invalid multibase encoding name %q", name)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	r := &registry{
+		codecs: map[Encoding]Codec{code: c},
+		infos:  map[Encoding]EncodingInfo{code: info},
+		byName: map[string]Encoding{name: code},
+	}
+	if old := registered.Load(); old != nil {
+		if _, ok := old.codecs[code]; ok {
+			return fmt.Errorf("multibase prefix %q is already registered", rune(code))
+		}
+		if _, ok := old.byName[name]; ok {
+			return fmt.Errorf("multibase encoding name %q is already registered", name)
+		}
+		for e, c := range old.codecs {
+			r.codecs[e] = c
+			r.infos[e] = old.infos[e]
+			r.byName[old.infos[e].Name] = e
+		}
+		r.all = slices.Clone(old.all)
+	}
+	i, _ := slices.BinarySearchFunc(r.all, code, func(info EncodingInfo, code Encoding) int {
+		return int(info.Encoding - code)
+	})
+	r.all = slices.Insert(r.all, i, info)
+	registered.Store(r)
+	return nil
+}
+
+// Lookup returns the description of the encoding registered with the
+// prefix code.
+func Lookup(code Encoding) (EncodingInfo, bool) {
+	info, ok := registered.Load().infos[code]
+	return info, ok
+}
+
+// LookupName returns the description of the encoding registered with
+// name.
+func LookupName(name string) (EncodingInfo, bool) {
+	r := registered.Load()
+	code, ok := r.byName[name]
+	return r.infos[code], ok
+}
+
+// All returns the description of every registered encoding, sorted by
+// prefix.
+func All() []EncodingInfo {
+	return slices.Clone(registered.Load().all)
+}
+
+// lookupCodec returns the codec registered for code
+func lookupCodec(code Encoding) (Codec, bool) {
+	c, ok := registered.Load().codecs[code]
+	return c, ok
+}
@@ -0,0 +1,278 @@
+package multibase
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// reverseCodec is a toy private encoding writing the data backwards
+type reverseCodec struct{}
+
+func (reverseCodec) AppendEncode(dst, src []byte) []byte {
+	for i := len(src) - 1; i >= 0; i-- {
+		dst = append(dst, src[i])
+	}
+	return dst
+}
+
+func (c reverseCodec) AppendDecode(dst, src []byte) ([]byte, error) {
+	return c.AppendEncode(dst, src), nil
+}
+
+func (reverseCodec) EncodedLen(n int) int {
+	return n
+}
+
+func (reverseCodec) DecodedLen(src []byte) (min, max int) {
+	return len(src), len(src)
+}
+
+const testReverse Encoding = '🧪'
+
+// registerTestReverse registers testReverse for the duration of the test
+func registerTestReverse(t *testing.T) {
+	old := registered.Load()
+	if err := Register(testReverse, "testreverse", reverseCodec{}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		registered.Store(old)
+	})
+}
+
+func TestRegister(t *testing.T) {
+	registerTestReverse(t)
+
+	encoded, err := Encode(testReverse, []byte("abc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if encoded != "🧪cba" {
+		t.Errorf("unexpected encoding %q", encoded)
+	}
+	e, out, err := Decode(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e != testReverse || !bytes.Equal(out, []byte("abc")) {
+		t.Errorf("unexpected decoding %c %q", e, out)
+	}
+
+	for _, name := range []string{"testreverse", "🧪"} {
+		enc, err := EncoderByName(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if enc.Encoding() != testReverse {
+			t.Errorf("EncoderByName(%q) returned %c", name, enc.Encoding())
+		}
+	}
+	if _, err := NewEncoder(testReverse); err != nil {
+		t.Error(err)
+	}
+	if _, ok := EncodingToStr[testReverse]; ok {
+		t.Error("registered encoding added to EncodingToStr")
+	}
+	if _, ok := Encodings["testreverse"]; ok {
+		t.Error("registered encoding added to Encodings")
+	}
+}
+
+func TestRegisterConflicts(t *testing.T) {
+	registerTestReverse(t)
+
+	for _, tc := range []struct {
+		code Encoding
+		name string
+		c    Codec
+	}{
+		{Base32, "private32", reverseCodec{}},
+		{Identity, "private", reverseCodec{}},
+		{'Q', "private", reverseCodec{}},
+		{'/', "private", reverseCodec{}},
+		{testReverse, "private", reverseCodec{}},
+		{'🧫', "base32", reverseCodec{}},
+		{'🧫', "testreverse", reverseCodec{}},
+		{'🧫', "x", reverseCodec{}},
+		{'🧫', "", reverseCodec{}},
+		{'🧫', "private", nil},
+		{-1, "private", reverseCodec{}},
+	} {
+		if err := Register(tc.code, tc.name, tc.c); err == nil {
+			t.Errorf("Register(%q, %q) should fail", rune(tc.code), tc.name)
+		}
+	}
+	if _, ok := lookupCodec('🧫'); ok {
+		t.Error("failed registration left an encoding behind")
+	}
+}
+
+func TestBuiltinsRegistered(t *testing.T) {
+	for _, b := range builtins {
+		if c, ok := lookupCodec(b.info.Encoding); !ok || c != b.codec {
+			t.Errorf("%s is not registered", b.info.Name)
+		}
+		if info, ok := LookupName(b.info.Name); !ok || info.Encoding != b.info.Encoding {
+			t.Errorf("%s is not registered by name", b.info.Name)
+		}
+	}
+	if n := len(registered.Load().codecs); n != len(builtins) {
+		t.Errorf("expected the built-in encodings only, got %d encodings", n)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	info, ok := Lookup(Base58BTC)
+	if !ok || info.Encoding != Base58BTC || info.Name != "base58btc" {
+		t.Errorf("unexpected Lookup result %v, %v", info, ok)
+	}
+	if info, ok := LookupName("base58btc"); !ok || info.Encoding != Base58BTC {
+		t.Errorf("unexpected LookupName result %v, %v", info, ok)
+	}
+	if _, ok := Lookup('q'); ok {
+		t.Error("Lookup should fail for an unknown prefix")
+	}
+	if _, ok := LookupName("base99"); ok {
+		t.Error("LookupName should fail for an unknown name")
+	}
+
+	all := All()
+	if len(all) != len(builtins) {
+		t.Fatalf("expected %d encodings, got %d", len(builtins), len(all))
+	}
+	for i := 1; i < len(all); i++ {
+		if all[i-1].Encoding >= all[i].Encoding {
+			t.Errorf("All is not sorted: %v before %v", all[i-1], all[i])
+		}
+	}
+	all[0].Name = "modified"
+	if All()[0].Name == "modified" {
+		t.Error("All returned the registry slice")
+	}
+}
+
+func TestRegisterConcurrent(t *testing.T) {
+	old := registered.Load()
+	t.Cleanup(func() { registered.Store(old) })
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := Register(Encoding('🧪'+i), fmt.Sprintf("testconcurrent%d", i), reverseCodec{}); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				_ = EncodingToStr[Base32]
+				_ = Encodings["base32"]
+			}
+		}()
+	}
+	wg.Wait()
+	if len(All()) != len(old.all)+4 {
+		t.Errorf("expected 4 more encodings, got %d", len(All())-len(old.all))
+	}
+}
+
+func TestLegacyMapsMutation(t *testing.T) {
+	delete(EncodingToStr, Base32)
+	delete(Encodings, "base58btc")
+	Encodings["base99"] = Base16
+	t.Cleanup(func() {
+		EncodingToStr[Base32] = "base32"
+		Encodings["base58btc"] = Base58BTC
+		delete(Encodings, "base99")
+	})
+
+	if _, err := NewEncoder(Base32); err != nil {
+		t.Error(err)
+	}
+	if _, err := EncoderByName("base58btc"); err != nil {
+		t.Error(err)
+	}
+	if _, err := EncoderByName("base99"); err == nil {
+		t.Error("EncoderByName should ignore the legacy maps")
+	}
+	if _, err := Encode(Base32, []byte("foo")); err != nil {
+		t.Error(err)
+	}
+}
+
+// leakyCodec is a registered codec keeping its buffers and writing to
+// its input, which the builtin codecs never do
+type leakyCodec struct {
+	reverseCodec
+	kept *[]byte
+}
+
+func (c leakyCodec) AppendEncode(dst, src []byte) []byte {
+	dst = c.reverseCodec.AppendEncode(dst, src)
+	*c.kept = dst
+	return dst
+}
+
+func (c leakyCodec) AppendDecode(dst, src []byte) ([]byte, error) {
+	dst, err := c.reverseCodec.AppendDecode(dst, src)
+	clear(src)
+	return dst, err
+}
+
+func TestRegisteredCodecBuffers(t *testing.T) {
+	const code Encoding = '🧫'
+	var kept []byte
+	old := registered.Load()
+	if err := Register(code, "testleaky", leakyCodec{kept: &kept}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		registered.Store(old)
+	})
+
+	encoders := map[string]func() string{
+		"Encode": func() string {
+			s, _ := Encode(code, []byte("abc"))
+			return s
+		},
+		"EncodeRaw": func() string {
+			s, _ := EncodeRaw(code, []byte("abc"))
+			return s
+		},
+		"EncodeToString": func() string {
+			return Encoding(code).Codec().EncodeToString([]byte("abc"))
+		},
+	}
+	for name, encode := range encoders {
+		s := encode()
+		want := s[:len(s)-3] + "cba"
+		clear(kept)
+		if s != want {
+			t.Errorf("%s string changed by the codec: %q", name, s)
+		}
+	}
+
+	s, _ := Encode(code, []byte("abc"))
+	prefix := len(string(rune(code)))
+	for _, decode := range []func(string) ([]byte, error){
+		func(s string) ([]byte, error) {
+			_, out, err := Decode(s)
+			return out, err
+		},
+		func(s string) ([]byte, error) {
+			return DecodeRaw(code, s[prefix:])
+		},
+	} {
+		out, err := decode(s)
+		if err != nil || string(out) != "abc" {
+			t.Errorf("decoding %q = %q, %v", s, out, err)
+		}
+	}
+	if s[prefix:] != "cba" {
+		t.Errorf("input string changed by the codec: %q", s)
+	}
+}
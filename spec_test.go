@@ -27,10 +27,12 @@ func TestSpec(t *testing.T) {
 		t.Fatal(err)
 	}
 	specEncodings := make(map[Encoding]string, len(values)-1)
+	specStatuses := make(map[Encoding]Status, len(values)-1)
 	for _, v := range values[1:] {
 		unicodeStr := v[0] // e.g. "U+007A"
 		character := v[1]
 		encoding := v[2]
+		status := v[4]
 
 		var code Encoding
 		if !strings.HasPrefix(unicodeStr, "U+") {
@@ -52,6 +54,16 @@ func TestSpec(t *testing.T) {
 		}
 
 		specEncodings[code] = encoding
+		switch status {
+		case "final":
+			specStatuses[code] = StatusFinal
+		case "draft", "candidate", "experimental":
+			specStatuses[code] = StatusDraft
+		case "reserved":
+			specStatuses[code] = StatusReserved
+		default:
+			t.Errorf("%s: unexpected status %q", unicodeStr, status)
+		}
 	}
 
 	for name, enc := range Encodings {
@@ -68,6 +80,19 @@ func TestSpec(t *testing.T) {
 		if specName != name {
 			t.Errorf("encoding %q (%c) has unexpected name %q", specName, enc, name)
 		}
+		if status := enc.Info().Status; status != specStatuses[enc] {
+			t.Errorf("encoding %q (%c) has status %s, expected %s", name, enc, status, specStatuses[enc])
+		}
+	}
+
+	// The prefixes the spec keeps out of use are reported as reserved
+	for code, name := range specEncodings {
+		if _, ok := EncodingToStr[code]; ok || name != "none" {
+			continue
+		}
+		if status := code.Info().Status; status != specStatuses[code] {
+			t.Errorf("prefix %q has status %s, expected %s", rune(code), status, specStatuses[code])
+		}
 	}
 }
 func TestSpecVectors(t *testing.T) {
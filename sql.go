@@ -0,0 +1,52 @@
+package multibase
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// Column stores bytes in a database as a multibase string. It
+// implements sql.Scanner and driver.Valuer.
+//
+// Scanning decodes TEXT columns with any supported encoding but
+// identity, whose binary data doesn't belong in TEXT, and takes BLOB
+// columns as is, NULL scans to nil Bytes. Values are written as TEXT
+// with Encoding, or Base64url when Encoding is left to its zero value.
+type Column struct {
+	Bytes    []byte
+	Encoding Encoding
+}
+
+// Scan implements sql.Scanner, Encoding is left unchanged.
+func (c *Column) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		c.Bytes = nil
+	case string:
+		enc, out, err := Decode(src)
+		if err == nil && enc == Identity {
+			err = allowedError(enc, nil)
+		}
+		if err != nil {
+			return err
+		}
+		c.Bytes = out
+	case []byte:
+		// the driver may reuse src
+		c.Bytes = append([]byte{}, src...)
+	default:
+		return fmt.Errorf("cannot scan %T into a multibase column", src)
+	}
+	return nil
+}
+
+// Value implements driver.Valuer, nil Bytes are written as NULL.
+func (c Column) Value() (driver.Value, error) {
+	if c.Bytes == nil {
+		return nil, nil
+	}
+	if c.Encoding == Identity {
+		return Encode(Base64url, c.Bytes)
+	}
+	return Encode(c.Encoding, c.Bytes)
+}
@@ -0,0 +1,152 @@
+package multibase
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sync"
+	"testing"
+)
+
+// fakeDriver is a database with a single column table: every Exec
+// inserts a row made of its argument, every Query returns all the rows
+type fakeDriver struct {
+	mu   sync.Mutex
+	rows []driver.Value
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+	return fakeConn{d}, nil
+}
+
+type fakeConn struct {
+	d *fakeDriver
+}
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) {
+	return fakeStmt(c), nil
+}
+
+func (fakeConn) Close() error {
+	return nil
+}
+
+func (fakeConn) Begin() (driver.Tx, error) {
+	return nil, driver.ErrSkip
+}
+
+type fakeStmt struct {
+	d *fakeDriver
+}
+
+func (fakeStmt) Close() error {
+	return nil
+}
+
+func (fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.d.rows = append(s.d.rows, args[0])
+	return driver.RowsAffected(1), nil
+}
+
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	return &fakeRows{rows: append([]driver.Value{}, s.d.rows...)}, nil
+}
+
+type fakeRows struct {
+	rows []driver.Value
+}
+
+func (*fakeRows) Columns() []string {
+	return []string{"id"}
+}
+
+func (*fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	dest[0], r.rows = r.rows[0], r.rows[1:]
+	return nil
+}
+
+var testDriver = &fakeDriver{}
+
+func init() {
+	sql.Register("multibasefake", testDriver)
+}
+
+func TestColumn(t *testing.T) {
+	db, err := sql.Open("multibasefake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	testDriver.rows = nil
+	for _, v := range []any{
+		Column{Bytes: []byte("hello"), Encoding: Base32},
+		Column{Bytes: []byte("world"), Encoding: Base58BTC},
+		Column{},
+		Column{Bytes: []byte("zero")},
+		"f68656c6c6f",
+		[]byte("raw"),
+	} {
+		if _, err := db.Exec("INSERT", v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if testDriver.rows[0] != "bnbswy3dp" || testDriver.rows[1] != "zEUYUqQf" || testDriver.rows[2] != nil || testDriver.rows[3] != "uemVybw" {
+		t.Errorf("unexpected values written %q", testDriver.rows)
+	}
+
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var got []Column
+	for rows.Next() {
+		c := Column{Encoding: Base36}
+		if err := rows.Scan(&c); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, c)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"hello", "world", "", "zero", "hello", "raw"}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d rows, got %d", len(expected), len(got))
+	}
+	for i, c := range got {
+		if string(c.Bytes) != expected[i] || c.Encoding != Base36 {
+			t.Errorf("row %d: expected %q, got %q (%v)", i, expected[i], c.Bytes, c.Encoding)
+		}
+	}
+	if got[2].Bytes != nil {
+		t.Error("NULL should scan to nil bytes")
+	}
+}
+
+func TestColumnScanErrors(t *testing.T) {
+	var c Column
+	for _, src := range []any{"", "q123", "f0g", "\x00raw", 42} {
+		if err := c.Scan(src); err == nil {
+			t.Errorf("scanning %#v should fail", src)
+		}
+	}
+}
@@ -0,0 +1,381 @@
+package multibase
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf8"
+)
+
+// ErrNotStreamable is returned by NewWriter and NewReader when the
+// selected encoding can not be processed incrementally. This is the case
+// for the big-number encodings (base10, base36, base58) where every
+// output character depends on the whole input, and for proquint.
+var ErrNotStreamable = fmt.Errorf("selected encoding can not be streamed")
+
+// errWriterClosed is returned by the Write and Close methods of a
+// stream encoder once it is closed
+var errWriterClosed = fmt.Errorf("multibase writer: %w", os.ErrClosed)
+
+// streamBlock describes the fixed size blocks of a streamable encoding:
+// decoded bytes always encode to encoded characters (runes for
+// base256emoji, bytes otherwise)
+type streamBlock struct {
+	decoded, encoded int
+}
+
+// streamBlocks holds the block sizes of every encoding that can be
+// streamed
+var streamBlocks = map[Encoding]streamBlock{
+	Identity:          {1, 1},
+	Base2:             {1, 8},
+	Base8:             {3, 8},
+	Base16:            {1, 2},
+	Base16Upper:       {1, 2},
+	Base32:            {5, 8},
+	Base32Upper:       {5, 8},
+	Base32pad:         {5, 8},
+	Base32padUpper:    {5, 8},
+	Base32hex:         {5, 8},
+	Base32hexUpper:    {5, 8},
+	Base32hexPad:      {5, 8},
+	Base32hexPadUpper: {5, 8},
+	Base32z:           {5, 8},
+	Base45:            {2, 3},
+	Base64:            {3, 4},
+	Base64url:         {3, 4},
+	Base64pad:         {3, 4},
+	Base64urlPad:      {3, 4},
+	Base256Emoji:      {1, 1},
+}
+
+// streamChunkSize bounds the amount of input encoded or decoded at once
+const streamChunkSize = 3 * 5 * 1024
+
+func streamBlockOf(base Encoding) (streamBlock, Codec, error) {
+	c, ok := lookupCodec(base)
+	if !ok {
+		return streamBlock{}, nil, ErrUnsupportedEncoding
+	}
+	b, ok := streamBlocks[base]
+	if !ok {
+		return streamBlock{}, nil, fmt.Errorf("%w: %s", ErrNotStreamable, base)
+	}
+	return b, c, nil
+}
+
+type writer struct {
+	base   Encoding
+	codec  Codec
+	w      io.Writer
+	size   int
+	prefix bool // the prefix has been written
+	closed bool
+	err    error
+	buf    [8]byte // leftover input, less than one block
+	nbuf   int
+	out    []byte // encoding buffer
+}
+
+// NewWriter returns a multibase stream encoder. Data written to the
+// returned writer is encoded with base and written to w, starting with
+// the multibase prefix. Full blocks are encoded as soon as they are
+// available, the trailing partial block (and padding) is only written
+// when the writer is closed, so the caller must Close it when done.
+// Closing does not close w, writing to or closing the writer again then
+// fails with an error matching os.ErrClosed.
+func NewWriter(base Encoding, w io.Writer) (io.WriteCloser, error) {
+	b, c, err := streamBlockOf(base)
+	if err != nil {
+		return nil, err
+	}
+	return &writer{base: base, codec: c, w: w, size: b.decoded}, nil
+}
+
+func (e *writer) Write(p []byte) (n int, err error) {
+	if e.closed {
+		return 0, errWriterClosed
+	}
+	if e.err != nil {
+		return 0, e.err
+	}
+
+	// Complete the leftover block first
+	if e.nbuf > 0 {
+		i := copy(e.buf[e.nbuf:e.size], p)
+		e.nbuf += i
+		n, p = i, p[i:]
+		if e.nbuf < e.size {
+			return n, nil
+		}
+		if e.err = e.encode(e.buf[:e.size]); e.err != nil {
+			return n, e.err
+		}
+		e.nbuf = 0
+	}
+
+	// Encode all the full blocks
+	for len(p) >= e.size {
+		nn := len(p) / e.size * e.size
+		if nn > streamChunkSize {
+			nn = streamChunkSize
+		}
+		if e.err = e.encode(p[:nn]); e.err != nil {
+			return n, e.err
+		}
+		n += nn
+		p = p[nn:]
+	}
+
+	// Keep the remaining partial block
+	e.nbuf = copy(e.buf[:], p)
+	n += e.nbuf
+	return n, nil
+}
+
+// Close flushes the multibase prefix, if nothing was written yet, and
+// any partially written block. The writer can't be used once closed.
+func (e *writer) Close() error {
+	if e.closed {
+		return errWriterClosed
+	}
+	e.closed = true
+	if e.err == nil && (e.nbuf > 0 || !e.prefix) {
+		e.err = e.encode(e.buf[:e.nbuf])
+		e.nbuf = 0
+	}
+	return e.err
+}
+
+// encode writes the encoding of src to the underlying writer, src must
+// be made of full blocks unless it is the end of the stream
+func (e *writer) encode(src []byte) error {
+	e.out = e.out[:0]
+	if !e.prefix {
+		e.out = utf8.AppendRune(e.out, rune(e.base))
+		e.prefix = true
+	}
+	e.out = e.codec.AppendEncode(e.out, src)
+	_, err := e.w.Write(e.out)
+	return err
+}
+
+type reader struct {
+	base     Encoding
+	codec    Codec
+	r        io.Reader
+	block    streamBlock
+	newlines bool   // line breaks are ignored, as the base32 and base64 decoders do
+	padding  bool   // the encoding ends with padding characters
+	trailing bool   // data after the padding is ignored, as the base32 decoders do
+	end      bool   // saw the end of data padding
+	buf      []byte // encoded input left to decode
+	off      int    // offset of buf in the stream
+	skipped  []int  // indexes of buf before which a line break was removed
+	out      []byte // decoded output left to read
+	outbuf   []byte
+	err      error
+}
+
+// NewReader returns a multibase stream decoder. It reads the multibase
+// prefix from r (one byte, or four for Base256Emoji) and returns the
+// selected Encoding along with a reader yielding the decoded data as
+// the rest of r is consumed. Only block based encodings can be streamed,
+// NewReader returns ErrNotStreamable for the others. Malformed input is
+// reported as a *DecodeError of kind ErrCorruptPayload, its Offset
+// counting the bytes of the whole stream. Like Decode, the data following
+// the padding of the base32 encodings is ignored. Unlike Decode, a base2
+// stream must be a multiple of 8 digits long.
+func NewReader(r io.Reader) (Encoding, io.Reader, error) {
+	var p [utf8.UTFMax]byte
+	n := 0
+	for n == 0 || !utf8.FullRune(p[:n]) {
+		if _, err := io.ReadFull(r, p[n:n+1]); err != nil {
+			if err == io.EOF {
+				if n == 0 {
+					return 0, nil, prefixError(nil)
+				}
+				err = io.ErrUnexpectedEOF
+			}
+			return -1, nil, err
+		}
+		n++
+	}
+
+	c, _ := utf8.DecodeRune(p[:n])
+	base := Encoding(c)
+	b, codec, err := streamBlockOf(base)
+	if err == ErrUnsupportedEncoding {
+		return -1, nil, prefixError(p[:n])
+	} else if err != nil {
+		return base, nil, err
+	}
+
+	d := &reader{
+		base:  base,
+		codec: codec,
+		r:     r,
+		block: b,
+		buf:   make([]byte, 0, streamChunkSize),
+		off:   n,
+	}
+	switch base {
+	case Base32pad, Base32padUpper, Base32hexPad, Base32hexPadUpper:
+		d.trailing = true
+		fallthrough
+	case Base64pad, Base64urlPad:
+		d.padding = true
+		fallthrough
+	case Base32, Base32Upper, Base32hex, Base32hexUpper, Base32z, Base64, Base64url:
+		d.newlines = true
+	}
+	return base, d, nil
+}
+
+func (d *reader) Read(p []byte) (int, error) {
+	for len(d.out) == 0 && d.err == nil {
+		d.fill()
+	}
+	if len(d.out) > 0 {
+		n := copy(p, d.out)
+		d.out = d.out[n:]
+		return n, nil
+	}
+	return 0, d.err
+}
+
+// fill reads more input and decodes all the complete blocks buffered
+func (d *reader) fill() {
+	n, err := d.r.Read(d.buf[len(d.buf):cap(d.buf)])
+	read := d.buf[len(d.buf) : len(d.buf)+n]
+	if d.newlines {
+		read = d.stripNewlines(read, len(d.buf))
+	}
+	d.buf = d.buf[:len(d.buf)+len(read)]
+
+	eof := err == io.EOF
+	k := len(d.buf)
+	if !eof {
+		k = d.blocksEnd()
+		if d.trailing {
+			k = d.paddingStart(k)
+		}
+	}
+	if derr := d.decode(d.buf[:k], eof); derr != nil {
+		d.err = derr
+		return
+	}
+	d.consume(k)
+
+	if err != nil {
+		d.err = err
+	}
+}
+
+// blocksEnd returns the length of the complete blocks at the start of
+// the buffered input
+func (d *reader) blocksEnd() int {
+	if d.base != Base256Emoji {
+		return len(d.buf) / d.block.encoded * d.block.encoded
+	}
+	// stop before a rune cut in the middle
+	for i := len(d.buf) - 1; i >= 0 && i >= len(d.buf)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(d.buf[i]) {
+			if !utf8.FullRune(d.buf[i:]) {
+				return i
+			}
+			break
+		}
+	}
+	return len(d.buf)
+}
+
+// paddingStart returns the length of the input that can be decoded
+// before the padding, at most k. The block holding the padding is kept
+// until the end of the stream, so that it is decoded along with the data
+// following it which Decode ignores, unless there is too much data after
+// the '=' for it to be padding.
+func (d *reader) paddingStart(k int) int {
+	i := bytes.IndexByte(d.buf, '=')
+	if i < 0 {
+		return k
+	}
+	if len(d.buf)-i-1 >= 8 {
+		// the '=' is corrupt data, let the codec report it
+		return len(d.buf)
+	}
+	return min(k, i/d.block.encoded*d.block.encoded)
+}
+
+// decode decodes chunk into the output buffer, chunk must be made of
+// complete blocks unless it is the end of the stream
+func (d *reader) decode(chunk []byte, final bool) error {
+	if len(chunk) == 0 {
+		return nil
+	}
+	if d.end {
+		return d.corrupt(chunk, corruptInputError{0, fmt.Errorf("data after padding")})
+	}
+	if final && d.base == Base2 && len(chunk)%8 != 0 {
+		return d.corrupt(chunk, corruptInputError{len(chunk) / 8 * 8, fmt.Errorf("incomplete byte")})
+	}
+
+	out, err := d.codec.AppendDecode(d.outbuf[:0], chunk)
+	if err != nil {
+		return d.corrupt(chunk, err)
+	}
+	d.outbuf, d.out = out, out
+	d.end = d.padding && chunk[len(chunk)-1] == '='
+	return nil
+}
+
+// corrupt returns the *DecodeError of err, returned by the codec
+// decoding chunk
+func (d *reader) corrupt(chunk []byte, err error) error {
+	e := payloadError(d.base, 0, chunk, err)
+	e.Offset = d.streamOffset(e.Offset)
+	return e
+}
+
+// streamOffset returns the offset in the stream of buf[i], counting the
+// line breaks removed before it
+func (d *reader) streamOffset(i int) int {
+	off := d.off + i
+	for _, j := range d.skipped {
+		if j > i {
+			break
+		}
+		off++
+	}
+	return off
+}
+
+// consume drops the first k bytes of buf, once decoded
+func (d *reader) consume(k int) {
+	d.off = d.streamOffset(k)
+	n := 0
+	for _, j := range d.skipped {
+		if j > k {
+			d.skipped[n] = j - k
+			n++
+		}
+	}
+	d.skipped = d.skipped[:n]
+	d.buf = d.buf[:copy(d.buf, d.buf[k:])]
+}
+
+// stripNewlines removes '\r' and '\n' from b in place, b being read at
+// buf[start:], and records where they were in skipped
+func (d *reader) stripNewlines(b []byte, start int) []byte {
+	n := 0
+	for _, c := range b {
+		if c == '\r' || c == '\n' {
+			d.skipped = append(d.skipped, start+n)
+			continue
+		}
+		b[n] = c
+		n++
+	}
+	return b[:n]
+}
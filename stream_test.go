@@ -0,0 +1,219 @@
+package multibase
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestWriter(t *testing.T) {
+	buf := make([]byte, 1000)
+	rand.Read(buf)
+
+	for base := range streamBlocks {
+		for _, l := range []int{0, 1, 2, 3, 4, 5, 7, 16, 137, 1000} {
+			expected, err := Encode(base, buf[:l])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// write in uneven pieces to exercise the leftover handling
+			for _, step := range []int{1, 2, 7, 1000} {
+				var out bytes.Buffer
+				w, err := NewWriter(base, &out)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for i := 0; i < l; i += step {
+					end := min(i+step, l)
+					n, err := w.Write(buf[i:end])
+					if err != nil {
+						t.Fatal(err)
+					}
+					if n != end-i {
+						t.Fatalf("short write: %d != %d", n, end-i)
+					}
+				}
+				if err := w.Close(); err != nil {
+					t.Fatal(err)
+				}
+				if out.String() != expected {
+					t.Errorf("stream encoding failed for %s (%d bytes in steps of %d), expected: %q, got: %q", EncodingToStr[base], l, step, expected, out.String())
+				}
+			}
+		}
+	}
+}
+
+func TestWriterClosed(t *testing.T) {
+	var out bytes.Buffer
+	w, err := NewWriter(Base16, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := w.Write([]byte("b")); n != 0 || !errors.Is(err, os.ErrClosed) {
+		t.Errorf("expected os.ErrClosed writing after Close, got %d, %v", n, err)
+	}
+	if err := w.Close(); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("expected os.ErrClosed closing twice, got %v", err)
+	}
+	if out.String() != "f61" {
+		t.Errorf("expected %q, got %q", "f61", out.String())
+	}
+}
+
+func TestWriterNotStreamable(t *testing.T) {
+	for _, base := range []Encoding{Base10, Base36, Base36Upper, Base58BTC, Base58Flickr, Proquint} {
+		_, err := NewWriter(base, &bytes.Buffer{})
+		if !errors.Is(err, ErrNotStreamable) {
+			t.Errorf("expected ErrNotStreamable for %s, got %v", EncodingToStr[base], err)
+		}
+	}
+	_, err := NewWriter('q', &bytes.Buffer{})
+	if !errors.Is(err, ErrUnsupportedEncoding) {
+		t.Errorf("expected ErrUnsupportedEncoding, got %v", err)
+	}
+}
+
+func TestReader(t *testing.T) {
+	buf := make([]byte, 50000)
+	rand.Read(buf)
+
+	for base := range streamBlocks {
+		for _, l := range []int{0, 1, 2, 3, 4, 5, 7, 16, 137, 50000} {
+			enc, err := Encode(base, buf[:l])
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, wrap := range []func(io.Reader) io.Reader{
+				func(r io.Reader) io.Reader { return r },
+				iotest.OneByteReader,
+				iotest.HalfReader,
+				iotest.DataErrReader,
+			} {
+				e, r, err := NewReader(wrap(strings.NewReader(enc)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if e != base {
+					t.Fatalf("got wrong encoding out, expected %s, got %d", EncodingToStr[base], e)
+				}
+				out, err := io.ReadAll(r)
+				if err != nil {
+					t.Fatalf("stream decoding failed for %s (%d bytes): %v", EncodingToStr[base], l, err)
+				}
+				if !bytes.Equal(buf[:l], out) {
+					t.Fatalf("stream decoding failed for %s (%d bytes), input wasn't the same as output", EncodingToStr[base], l)
+				}
+			}
+		}
+	}
+}
+
+func TestReaderLineBreaks(t *testing.T) {
+	_, r, err := NewReader(strings.NewReader("mSGVs\nbG8\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "Hello" {
+		t.Errorf("expected %q, got %q", "Hello", out)
+	}
+}
+
+func TestReaderCorrupt(t *testing.T) {
+	for _, tc := range []struct {
+		data   string
+		offset int
+	}{
+		{"f0011zz", 5},
+		{"f001", 4},
+		{"MQQ==QQ==", 5},
+		{"bmfrgg!zdf", 6},
+		{"00100000101", 9},
+		{"0010000012100000", 9},
+		{"7000!", 4},
+		{"RGGW", 1},
+		{"mSGVs\nbG!v", 8},
+		{"mSGVs\r\nbG8\r\nA!", 13},
+		{"MSGVsbG8=\r\nQQ==", 11},
+		{"🚀🚀a", 8},
+	} {
+		_, r, err := NewReader(iotest.OneByteReader(strings.NewReader(tc.data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = io.ReadAll(r)
+		var cerr *DecodeError
+		if !errors.As(err, &cerr) || cerr.Kind != ErrCorruptPayload {
+			t.Errorf("decoding %q: expected a DecodeError of kind ErrCorruptPayload, got %v", tc.data, err)
+			continue
+		}
+		if cerr.Offset != tc.offset {
+			t.Errorf("decoding %q: expected the error at offset %d, got %d (%v)", tc.data, tc.offset, cerr.Offset, err)
+		}
+	}
+}
+
+// TestReaderPadding checks that the data following the padding of the
+// base32 encodings is handled like Decode does
+func TestReaderPadding(t *testing.T) {
+	const tail = "MZXW6YTBOI======mzxw6\n"
+	for _, base := range []Encoding{Base32pad, Base32padUpper, Base32hexPad, Base32hexPadUpper, Base64pad} {
+		for l := range 6 {
+			encoded, err := Encode(base, []byte("hello world")[:l+5])
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range len(tail) + 1 {
+				data := encoded + tail[:i]
+				_, expected, expectedErr := Decode(data)
+				for _, r := range []io.Reader{strings.NewReader(data), iotest.OneByteReader(strings.NewReader(data))} {
+					_, d, err := NewReader(r)
+					if err != nil {
+						t.Fatal(err)
+					}
+					out, err := io.ReadAll(d)
+					var de, expectedDe *DecodeError
+					if (err == nil) != (expectedErr == nil) {
+						t.Errorf("decoding %q: expected error %v, got %v", data, expectedErr, err)
+					} else if errors.As(err, &de) && errors.As(expectedErr, &expectedDe) && de.Offset != expectedDe.Offset {
+						t.Errorf("decoding %q: expected error %v, got %v", data, expectedErr, err)
+					} else if err == nil && !bytes.Equal(out, expected) {
+						t.Errorf("decoding %q: expected %q, got %q", data, expected, out)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestReaderPrefix(t *testing.T) {
+	if _, _, err := NewReader(strings.NewReader("")); err == nil {
+		t.Error("shouldn't be able to decode an empty stream")
+	}
+	if e, _, err := NewReader(strings.NewReader("q")); !errors.Is(err, ErrUnsupportedEncoding) {
+		t.Errorf("expected ErrUnsupportedEncoding, got %d, %v", e, err)
+	}
+	if e, _, err := NewReader(strings.NewReader("z36UQrhJq9fNDS7DiAHM9YXqDHMPfr4EMArvt")); e != Base58BTC || !errors.Is(err, ErrNotStreamable) {
+		t.Errorf("expected ErrNotStreamable, got %d, %v", e, err)
+	}
+	if _, _, err := NewReader(strings.NewReader("🚀"[:2])); err != io.ErrUnexpectedEOF {
+		t.Errorf("expected io.ErrUnexpectedEOF on a truncated prefix, got %v", err)
+	}
+}
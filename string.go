@@ -0,0 +1,69 @@
+package multibase
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// Multibase is a multibase string, a prefix selecting the encoding
+// followed by the encoded data. Values returned by Parse are known to be
+// valid.
+type Multibase string
+
+// Parse validates s and returns it as a Multibase.
+func Parse(s string) (Multibase, error) {
+	m := Multibase(s)
+	if err := m.Validate(); err != nil {
+		return "", err
+	}
+	return m, nil
+}
+
+// Encoding returns the encoding selected by the prefix of m, or -1 when m
+// is empty.
+func (m Multibase) Encoding() Encoding {
+	if len(m) == 0 {
+		return -1
+	}
+	r, _ := utf8.DecodeRuneInString(string(m))
+	return Encoding(r)
+}
+
+// Bytes returns the data encoded in m.
+func (m Multibase) Bytes() ([]byte, error) {
+	_, out, err := Decode(string(m))
+	return out, err
+}
+
+// Convert returns the multibase string of the data of m encoded with to.
+func (m Multibase) Convert(to Encoding) (Multibase, error) {
+	data, err := m.Bytes()
+	if err != nil {
+		return "", err
+	}
+	s, err := Encode(to, data)
+	return Multibase(s), err
+}
+
+// Validate checks that m is a valid multibase string.
+func (m Multibase) Validate() error {
+	_, err := Validate(string(m))
+	return err
+}
+
+// Equal reports whether m and other encode the same data, whatever their
+// encodings. Invalid multibase strings are only equal to themselves.
+func (m Multibase) Equal(other Multibase) bool {
+	if m == other {
+		return true
+	}
+	a, err := m.Bytes()
+	if err != nil {
+		return false
+	}
+	b, err := other.Bytes()
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(a, b)
+}
@@ -0,0 +1,64 @@
+package multibase
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMultibase(t *testing.T) {
+	m, err := Parse(encodedSamples[Base58BTC])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Encoding() != Base58BTC {
+		t.Errorf("unexpected encoding %v", m.Encoding())
+	}
+	data, err := m.Bytes()
+	if err != nil || !bytes.Equal(data, sampleBytes) {
+		t.Errorf("unexpected bytes %q, %v", data, err)
+	}
+
+	for encoding, sample := range encodedSamples {
+		converted, err := m.Convert(encoding)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(converted) != sample {
+			t.Errorf("Convert to %v: expected %q, got %q", encoding, sample, converted)
+		}
+		if !m.Equal(converted) || !converted.Equal(m) {
+			t.Errorf("%q and %q should be equal", m, converted)
+		}
+	}
+
+	if m.Equal(Multibase("zEUYUqQf")) {
+		t.Error("different data should not be equal")
+	}
+	if Multibase("f0g").Equal(Multibase("f0g0")) {
+		t.Error("invalid strings should not be equal")
+	}
+	if !Multibase("f0g").Equal(Multibase("f0g")) {
+		t.Error("identical strings should be equal")
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, s := range []string{"", "q123", "f0g", "bmzxw6!"} {
+		m, err := Parse(s)
+		if err == nil || m != "" {
+			t.Errorf("Parse(%q) should fail, got %q", s, m)
+		}
+		if err := Multibase(s).Validate(); err == nil {
+			t.Errorf("Validate(%q) should fail", s)
+		}
+		if _, err := Multibase(s).Convert(Base32); err == nil {
+			t.Errorf("Convert(%q) should fail", s)
+		}
+	}
+	if e := Multibase("").Encoding(); e != -1 {
+		t.Errorf("expected -1 for an empty string, got %d", e)
+	}
+	if _, err := Multibase("bmzxw6").Convert('q'); err == nil {
+		t.Error("Convert to an unsupported encoding should fail")
+	}
+}
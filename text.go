@@ -0,0 +1,45 @@
+package multibase
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// String returns the name of the encoding, or Encoding(n) when it isn't
+// registered.
+func (e Encoding) String() string {
+	if info, ok := Lookup(e); ok {
+		return info.Name
+	}
+	return "Encoding(" + strconv.Itoa(int(e)) + ")"
+}
+
+// MarshalText implements encoding.TextMarshaler, the encoding is
+// represented by its name.
+func (e Encoding) MarshalText() ([]byte, error) {
+	info, ok := Lookup(e)
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedEncoding, e)
+	}
+	return []byte(info.Name), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, it accepts the
+// name of an encoding or its prefix character like EncoderByName.
+func (e *Encoding) UnmarshalText(text []byte) error {
+	return e.Set(string(text))
+}
+
+// Set sets the encoding from its name or its prefix character, making
+// *Encoding a flag.Value:
+//
+//	base := multibase.Base32
+//	flag.Var(&base, "base", "multibase encoding of the output")
+func (e *Encoding) Set(s string) error {
+	enc, err := EncoderByName(s)
+	if err != nil {
+		return err
+	}
+	*e = enc.Encoding()
+	return nil
+}
@@ -0,0 +1,83 @@
+package multibase
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestEncodingString(t *testing.T) {
+	for e, s := range map[Encoding]string{
+		Base58BTC:    "base58btc",
+		Identity:     "identity",
+		Base256Emoji: "base256emoji",
+		'q':          "Encoding(113)",
+	} {
+		if e.String() != s {
+			t.Errorf("expected %q, got %q", s, e.String())
+		}
+	}
+	if s := fmt.Sprint(Encoding(Base32)); s != "base32" {
+		t.Errorf("expected base32, got %q", s)
+	}
+}
+
+func TestEncodingText(t *testing.T) {
+	type config struct {
+		Base Encoding `json:"base"`
+	}
+
+	out, err := json.Marshal(config{Base: Base36})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"base":"base36"}` {
+		t.Errorf("unexpected json %s", out)
+	}
+	if _, err := json.Marshal(config{Base: 'q'}); err == nil {
+		t.Error("marshalling an unknown encoding should fail")
+	}
+
+	for text, expected := range map[string]Encoding{
+		`{"base":"base36"}`:       Base36,
+		`{"base":"z"}`:            Base58BTC,
+		`{"base":"🚀"}`:            Base256Emoji,
+		`{"base":"base256emoji"}`: Base256Emoji,
+	} {
+		var c config
+		if err := json.Unmarshal([]byte(text), &c); err != nil {
+			t.Fatal(err)
+		}
+		if c.Base != expected {
+			t.Errorf("%s: expected %v, got %v", text, expected, c.Base)
+		}
+	}
+	for _, text := range []string{`{"base":""}`, `{"base":"q"}`, `{"base":"base99"}`} {
+		var c config
+		if err := json.Unmarshal([]byte(text), &c); err == nil {
+			t.Errorf("%s: expected an error", text)
+		}
+	}
+}
+
+func TestEncodingFlag(t *testing.T) {
+	base := Encoding(Base32)
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(&base, "base", "multibase encoding")
+
+	if f := fs.Lookup("base"); f.DefValue != "base32" {
+		t.Errorf("unexpected default value %q", f.DefValue)
+	}
+	if err := fs.Parse([]string{"-base", "base58btc"}); err != nil {
+		t.Fatal(err)
+	}
+	if base != Base58BTC {
+		t.Errorf("expected base58btc, got %v", base)
+	}
+	if err := fs.Parse([]string{"-base", "nope"}); err == nil {
+		t.Error("parsing an unknown encoding should fail")
+	}
+}
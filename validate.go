@@ -0,0 +1,58 @@
+package multibase
+
+// validator is implemented by the codecs that can check their input
+// without decoding it, which all the builtin codecs do
+type validator interface {
+	validate(src []byte) error
+}
+
+// Validate checks that s is a valid multibase string and returns its
+// encoding, without decoding it nor allocating. The big-number encodings
+// (base10, base36, base58) only have their alphabet checked, which is
+// linear unlike decoding them. The encodings added with Register are
+// checked by decoding them, as is everything when DefaultDecodeOptions
+// are strict. Validate fails exactly when Decode does, the limits of
+// DefaultDecodeOptions included.
+func Validate(s string) (Encoding, error) {
+	return validate(stringBytes(s), nil)
+}
+
+// ValidateAs is like Validate, but also fails with a *DecodeError of
+// kind ErrEncodingNotAllowed when the encoding of s isn't one of allowed.
+func ValidateAs(s string, allowed ...Encoding) (Encoding, error) {
+	if allowed == nil {
+		allowed = []Encoding{}
+	}
+	return validate(stringBytes(s), allowed)
+}
+
+// validate checks src within the limits of DefaultDecodeOptions, so
+// that it fails exactly when decoding does. The encoding must be part of
+// allowed unless it is nil.
+func validate(src []byte, allowed []Encoding) (Encoding, error) {
+	o := DefaultDecodeOptions
+	if o.Strict {
+		// the canonical encoding is only known from the decoded data
+		enc, _, err := o.appendDecode(nil, src, allowed)
+		return enc, err
+	}
+
+	enc, c, data, err := o.parse(src, allowed)
+	if err != nil {
+		return enc, err
+	}
+	v, ok := c.(validator)
+	if _, max := c.DecodedLen(data); o.MaxOutputLen > 0 && max > o.MaxOutputLen {
+		// the decoded length of the big-number encodings is only known
+		// by decoding
+		ok = false
+	}
+	if !ok {
+		_, err = o.appendDecodePayload(nil, enc, c, len(src)-len(data), data)
+		return enc, err
+	}
+	if err := v.validate(data); err != nil {
+		return enc, payloadError(enc, len(src)-len(data), data, err)
+	}
+	return enc, nil
+}
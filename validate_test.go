@@ -0,0 +1,151 @@
+package multibase
+
+import (
+	"crypto/rand"
+	"errors"
+	mrand "math/rand/v2"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	for encoding, sample := range encodedSamples {
+		e, err := Validate(sample)
+		if err != nil {
+			t.Errorf("Validate failed for %v: %v", encoding, err)
+		}
+		if e != encoding {
+			t.Errorf("wrong encoding code, expected: %c (%d), got %c (%d)", encoding, encoding, e, e)
+		}
+	}
+
+	for _, s := range []string{
+		"", "q1", "Qm", "f0g", "f001", "bmzxw6!", "cmzxw6=", "zI0", "k!", "9a",
+		"00102", "7001!", "RGGW", "mZm9v!", "MZm8", "pro-hidoj-", "\U0001F680a",
+	} {
+		_, _, decodeErr := Decode(s)
+		_, err := Validate(s)
+		if err == nil || !reflect.DeepEqual(err, decodeErr) {
+			t.Errorf("Validate(%q) = %v, Decode failed with %v", s, err, decodeErr)
+		}
+	}
+}
+
+func TestValidateAs(t *testing.T) {
+	if e, err := ValidateAs(encodedSamples[Base32], Base36, Base32); err != nil || e != Base32 {
+		t.Errorf("unexpected result %v, %v", e, err)
+	}
+	e, err := ValidateAs(encodedSamples[Base58BTC], Base36, Base32)
+	var derr *DecodeError
+	if !errors.As(err, &derr) || !errors.Is(err, ErrEncodingNotAllowed) || e != Base58BTC || derr.Encoding != Base58BTC {
+		t.Errorf("expected ErrEncodingNotAllowed, got %v, %v", e, err)
+	}
+	if _, err := ValidateAs(encodedSamples[Base32]); !errors.Is(err, ErrEncodingNotAllowed) {
+		t.Errorf("expected ErrEncodingNotAllowed with no allowed encoding, got %v", err)
+	}
+	if _, err := ValidateAs("b!", Base32); !errors.Is(err, ErrCorruptPayload) {
+		t.Errorf("expected ErrCorruptPayload, got %v", err)
+	}
+	if _, err := ValidateAs("q1", Base32); !errors.Is(err, ErrUnknownPrefix) {
+		t.Errorf("expected ErrUnknownPrefix, got %v", err)
+	}
+}
+
+// TestValidateRandom checks that Validate fails exactly like Decode on
+// random, mostly invalid, input
+func TestValidateRandom(t *testing.T) {
+	rng := mrand.New(mrand.NewPCG(1, 2))
+	for _, b := range builtins {
+		if _, ok := b.codec.(validator); !ok {
+			t.Errorf("%s has no validator", b.info.Name)
+		}
+
+		symbols := []rune(b.info.Alphabet + "=-\r\n !aAzZ01")
+		for range 5000 {
+			s := []rune{rune(b.info.Encoding)}
+			if b.info.Encoding == Proquint && rng.IntN(2) == 0 {
+				s = append(s, []rune(proquintPrefix)...)
+			}
+			for n := rng.IntN(20); n > 0; n-- {
+				s = append(s, symbols[rng.IntN(len(symbols))])
+			}
+			_, _, decodeErr := Decode(string(s))
+			_, err := Validate(string(s))
+			if !reflect.DeepEqual(err, decodeErr) {
+				t.Errorf("Validate(%q) = %v, Decode gave %v", string(s), err, decodeErr)
+			}
+		}
+	}
+}
+
+func TestValidateAllocs(t *testing.T) {
+	for _, size := range []int{1000, 100 << 10} {
+		data := make([]byte, size)
+		rand.Read(data)
+		for _, info := range All() {
+			encoded, err := Encode(info.Encoding, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			allocs := testing.AllocsPerRun(10, func() {
+				if _, err := Validate(encoded); err != nil {
+					t.Fatal(err)
+				}
+			})
+			if allocs != 0 {
+				t.Errorf("Validate with %s of %d bytes allocated %v times", info.Name, size, allocs)
+			}
+		}
+	}
+}
+
+func BenchmarkValidate(b *testing.B) {
+	data := make([]byte, 1024)
+	rand.Read(data)
+	for _, name := range benchmarkCodecs {
+		encoded, _ := Encode(Encodings[name], data)
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				Validate(encoded)
+			}
+		})
+	}
+}
+
+// TestValidateDefaultOptions checks that Validate, Parse and
+// Decoder.Validate fail exactly like Decode under DefaultDecodeOptions
+func TestValidateDefaultOptions(t *testing.T) {
+	defer func(o DecodeOptions) { DefaultDecodeOptions = o }(DefaultDecodeOptions)
+
+	inputs := []string{
+		"f68656c6c6f", "f6869", "zCn8eVZg", "z1111", "z11111", "z" + strings.Repeat("z", 30),
+		"bMZXW6", "bmzxw6", "Bmzxw6", "pro-hidoj", "prohidoj", "f0g",
+	}
+	d := MustNewDecoder(Base16, Base32, Base58BTC, Proquint)
+	for _, o := range []DecodeOptions{
+		{},
+		{MaxInputLen: 10},
+		{MaxBigNumberInputLen: 8},
+		{MaxOutputLen: 3},
+		{MaxOutputLen: 4},
+		{Strict: true},
+		{Strict: true, MaxOutputLen: 2},
+	} {
+		DefaultDecodeOptions = o
+		for _, s := range inputs {
+			_, _, decodeErr := Decode(s)
+			if _, err := Validate(s); !reflect.DeepEqual(err, decodeErr) {
+				t.Errorf("%+v: Validate(%q) = %v, Decode gave %v", o, s, err, decodeErr)
+			}
+			if _, err := Parse(s); !reflect.DeepEqual(err, decodeErr) {
+				t.Errorf("%+v: Parse(%q) = %v, Decode gave %v", o, s, err, decodeErr)
+			}
+			_, _, decodeErr = d.Decode(s)
+			if _, err := d.Validate(s); !reflect.DeepEqual(err, decodeErr) {
+				t.Errorf("%+v: Decoder.Validate(%q) = %v, Decoder.Decode gave %v", o, s, err, decodeErr)
+			}
+		}
+	}
+}